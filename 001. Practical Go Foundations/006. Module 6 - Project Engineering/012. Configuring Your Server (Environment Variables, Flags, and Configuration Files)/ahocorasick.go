@@ -0,0 +1,278 @@
+package nlp
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+/*
+Multi-pattern matching.
+Looking for thousands of phrases (a gazetteer, alert keywords, a profanity list) with one regular expression each
+means going over the text thousands of times.
+An Aho-Corasick automaton finds them all in one pass: it's a trie of the patterns, where every node also has a "fail" link
+to the longest suffix of its path that is also in the trie. When the next rune doesn't continue the current path,
+the matcher follows fail links instead of going back in the text.
+With LeftmostLongest, the matcher keeps the best match so far, and reports it as soon as the automaton's path
+starts after it (no later match can start before or at it), then starts over at its end.
+*/
+
+// MatcherOptions are the options of a Matcher.
+type MatcherOptions struct {
+	IgnoreCase bool // Match "Baker Street" in "BAKER STREET".
+	WholeWords bool // Only match at word boundaries: "cat" doesn't match in "concatenate".
+	// LeftmostLongest returns non overlapping matches: at every position, the longest pattern that starts there,
+	// and the search goes on after it, e.g. "new york" and not "york" in "new york".
+	// Without it, FindAll returns all the matches, including overlapping ones.
+	LeftmostLongest bool
+}
+
+// Match is a pattern found in a text.
+type Match struct {
+	Pattern int `json:"pattern"` // Index in the patterns given to NewMatcher.
+	Start   int `json:"start"`   // Byte offsets in the text.
+	End     int `json:"end"`
+}
+
+// Matcher finds many patterns at once.
+type Matcher struct {
+	opts  MatcherOptions
+	nodes []acNode
+	runes []int // Length of every pattern, in runes.
+}
+
+// acNode is a node of the automaton, the root is nodes[0].
+type acNode struct {
+	next     map[rune]int32
+	fail     int32
+	output   int32 // Index of the pattern that ends here, -1 if none.
+	dictLink int32 // Closest node on the fail path with an output, -1 if none.
+	depth    int32 // Length of the path to the node, in runes.
+}
+
+// NewMatcher returns a Matcher for patterns, which must not be empty.
+func NewMatcher(patterns []string, opts MatcherOptions) (*Matcher, error) {
+	m := &Matcher{
+		opts:  opts,
+		nodes: []acNode{{next: make(map[rune]int32), output: -1, dictLink: -1}},
+		runes: make([]int, len(patterns)),
+	}
+
+	for i, p := range patterns {
+		if p == "" {
+			return nil, errors.New("empty pattern")
+		}
+		n := int32(0)
+		for _, r := range p {
+			r = m.fold(r)
+			child, ok := m.nodes[n].next[r]
+			if !ok {
+				child = int32(len(m.nodes))
+				m.nodes = append(m.nodes, acNode{next: make(map[rune]int32), output: -1, dictLink: -1, depth: m.nodes[n].depth + 1})
+				m.nodes[n].next[r] = child
+			}
+			n = child
+			m.runes[i]++
+		}
+		if m.nodes[n].output == -1 { // Duplicates: the first pattern wins.
+			m.nodes[n].output = int32(i)
+		}
+	}
+
+	// Fail links, breadth first so that the links of shorter paths are set first.
+	queue := []int32{0}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+
+		// Sort the runes, so the automaton doesn't depend on map order.
+		runes := make([]rune, 0, len(m.nodes[n].next))
+		for r := range m.nodes[n].next {
+			runes = append(runes, r)
+		}
+		sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+
+		for _, r := range runes {
+			child := m.nodes[n].next[r]
+			if n != 0 {
+				f := m.nodes[n].fail
+				for f != 0 && !m.hasNext(f, r) {
+					f = m.nodes[f].fail
+				}
+				if next, ok := m.nodes[f].next[r]; ok {
+					m.nodes[child].fail = next
+				}
+			}
+			f := m.nodes[child].fail
+			if m.nodes[f].output != -1 {
+				m.nodes[child].dictLink = f
+			} else {
+				m.nodes[child].dictLink = m.nodes[f].dictLink
+			}
+			queue = append(queue, child)
+		}
+	}
+	return m, nil
+}
+
+func (m *Matcher) hasNext(n int32, r rune) bool {
+	_, ok := m.nodes[n].next[r]
+	return ok
+}
+
+// fold returns the smallest rune that r is equal to under simple case folding, with IgnoreCase.
+// All the case variants of a letter fold to the same rune, e.g. "σ", "ς" and "Σ" to "Σ", which unicode.ToLower doesn't do.
+func (m *Matcher) fold(r rune) rune {
+	if !m.opts.IgnoreCase {
+		return r
+	}
+	folded := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		folded = min(folded, f)
+	}
+	return folded
+}
+
+// step returns the node after n for the rune r.
+func (m *Matcher) step(n int32, r rune) int32 {
+	r = m.fold(r)
+	for n != 0 && !m.hasNext(n, r) {
+		n = m.nodes[n].fail
+	}
+	if next, ok := m.nodes[n].next[r]; ok {
+		return next
+	}
+	return n
+}
+
+// scan calls yield for every match in text, by end position, until it returns false.
+func (m *Matcher) scan(text string, yield func(Match) bool) {
+	// starts[i] is the byte offset of the i-th rune, to find where matches start.
+	var starts []int
+	n := int32(0)
+	for offset, r := range text {
+		starts = append(starts, offset)
+		_, size := utf8.DecodeRuneInString(text[offset:]) // Not utf8.RuneLen(r), r can be RuneError for invalid bytes.
+		end := offset + size
+		n = m.step(n, r)
+
+		for out := n; out != -1; out = m.nodes[out].dictLink {
+			p := m.nodes[out].output
+			if p == -1 {
+				continue
+			}
+			match := Match{Pattern: int(p), Start: starts[len(starts)-m.runes[p]], End: end}
+			if m.opts.WholeWords && !isWordBoundary(text, match.Start, match.End) {
+				continue
+			}
+			if !yield(match) {
+				return
+			}
+		}
+	}
+}
+
+// leftmostLongest calls yield for the non overlapping, leftmost-longest matches in text, in order,
+// until it returns false.
+func (m *Matcher) leftmostLongest(text string, yield func(Match) bool) {
+	var starts []int
+	n := int32(0)
+	best := Match{Start: -1}
+	for offset := 0; offset < len(text) || best.Start >= 0; {
+		// Matches found from now on start at or after pathStart: the path to n, or the end of the text.
+		pathStart, end := len(text), offset
+		if offset < len(text) {
+			starts = append(starts, offset)
+			r, size := utf8.DecodeRuneInString(text[offset:])
+			end = offset + size
+			n = m.step(n, r)
+			pathStart = end
+			if n != 0 {
+				pathStart = starts[len(starts)-int(m.nodes[n].depth)]
+			}
+		}
+		if best.Start >= 0 && pathStart > best.Start {
+			if !yield(best) {
+				return
+			}
+			// Start over after the match, the text read past its end can hold the next one.
+			offset, n, starts, best = best.End, 0, starts[:0], Match{Start: -1}
+			continue
+		}
+
+		for out := n; out != -1; out = m.nodes[out].dictLink {
+			p := m.nodes[out].output
+			if p == -1 {
+				continue
+			}
+			match := Match{Pattern: int(p), Start: starts[len(starts)-m.runes[p]], End: end}
+			if m.opts.WholeWords && !isWordBoundary(text, match.Start, match.End) {
+				continue
+			}
+			if best.Start < 0 || match.Start < best.Start || (match.Start == best.Start && match.End > best.End) {
+				best = match
+			}
+		}
+		offset = end
+	}
+}
+
+// isWordBoundary reports whether text[start:end] doesn't start or end in the middle of a word.
+func isWordBoundary(text string, start, end int) bool {
+	before, _ := utf8.DecodeLastRuneInString(text[:start])
+	after, _ := utf8.DecodeRuneInString(text[end:])
+	return !(start > 0 && isWordRune(before)) && !(end < len(text) && isWordRune(after))
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r) || r == '_'
+}
+
+// FindAll returns the matches in text, ordered by start, longest first at the same start.
+func (m *Matcher) FindAll(text string) []Match {
+	var matches []Match
+	add := func(match Match) bool {
+		matches = append(matches, match)
+		return true
+	}
+	if m.opts.LeftmostLongest {
+		m.leftmostLongest(text, add)
+		return matches
+	}
+
+	m.scan(text, add)
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Start != matches[j].Start {
+			return matches[i].Start < matches[j].Start
+		}
+		return matches[i].End > matches[j].End
+	})
+	return matches
+}
+
+// Contains reports whether text contains one of the patterns. It stops at the first match.
+func (m *Matcher) Contains(text string) bool {
+	found := false
+	m.scan(text, func(Match) bool {
+		found = true
+		return false
+	})
+	return found
+}
+
+// ReplaceAll returns text with every match replaced by repl(match), e.g. to mask profanities.
+// Matches are leftmost-longest, whatever the options, so they don't overlap.
+func (m *Matcher) ReplaceAll(text string, repl func(Match) string) string {
+	var sb strings.Builder
+	last := 0
+	m.leftmostLongest(text, func(match Match) bool {
+		sb.WriteString(text[last:match.Start])
+		sb.WriteString(repl(match))
+		last = match.End
+		return true
+	})
+	sb.WriteString(text[last:])
+	return sb.String()
+}
@@ -0,0 +1,150 @@
+package nlp
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// matchTexts returns the text of every match.
+func matchTexts(text string, matches []Match) []string {
+	var out []string
+	for _, m := range matches {
+		out = append(out, text[m.Start:m.End])
+	}
+	return out
+}
+
+func TestMatcher(t *testing.T) {
+	patterns := []string{"he", "she", "his", "hers", "new york", "york", "new"}
+	text := "ushers in New York, usher his hers"
+
+	var cases = []struct {
+		name     string
+		opts     MatcherOptions
+		expected []string
+	}{
+		{"all", MatcherOptions{}, []string{"she", "hers", "he", "she", "he", "his", "hers", "he"}},
+		{"ignore case", MatcherOptions{IgnoreCase: true},
+			[]string{"she", "hers", "he", "New York", "New", "York", "she", "he", "his", "hers", "he"}},
+		{"whole words", MatcherOptions{IgnoreCase: true, WholeWords: true},
+			[]string{"New York", "New", "York", "his", "hers"}},
+		{"leftmost longest", MatcherOptions{IgnoreCase: true, LeftmostLongest: true},
+			[]string{"she", "New York", "she", "his", "hers"}},
+		{"whole words leftmost longest", MatcherOptions{IgnoreCase: true, WholeWords: true, LeftmostLongest: true},
+			[]string{"New York", "his", "hers"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := NewMatcher(patterns, tc.opts)
+			require.NoError(t, err)
+			matches := m.FindAll(text)
+			require.Equal(t, tc.expected, matchTexts(text, matches))
+			for _, match := range matches {
+				require.True(t, strings.EqualFold(patterns[match.Pattern], text[match.Start:match.End]))
+			}
+		})
+	}
+
+	_, err := NewMatcher([]string{"a", ""}, MatcherOptions{})
+	require.Error(t, err)
+}
+
+func TestMatcherUnicode(t *testing.T) {
+	m, err := NewMatcher([]string{"café", "straße", "ΣΟΦΊΑ"}, MatcherOptions{IgnoreCase: true, WholeWords: true})
+	require.NoError(t, err)
+
+	text := "Un CAFÉ, une STRAßE, σοφία, et un cafés."
+	require.Equal(t, []string{"CAFÉ", "STRAßE", "σοφία"}, matchTexts(text, m.FindAll(text)))
+	require.True(t, m.Contains(text))
+	require.False(t, m.Contains("cafés\xff"))
+
+	// Letters with more than two cases: final sigma, the Kelvin sign.
+	m, err = NewMatcher([]string{"λόγος", "kelvin"}, MatcherOptions{IgnoreCase: true})
+	require.NoError(t, err)
+	text = "ΛΌΓΟΣ λόγοσ λόγος \u212Aelvin"
+	require.Equal(t, []string{"ΛΌΓΟΣ", "λόγοσ", "λόγος", "\u212Aelvin"}, matchTexts(text, m.FindAll(text)))
+}
+
+// Compare leftmost-longest matches found while scanning to filtering all the matches.
+func TestMatcherLeftmostLongest(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	word := func(n int) string {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = "ab "[rng.IntN(3)]
+		}
+		return string(b)
+	}
+
+	for range 200 {
+		var patterns []string
+		for range 1 + rng.IntN(6) {
+			patterns = append(patterns, word(1+rng.IntN(4)))
+		}
+		text := word(rng.IntN(40))
+		for _, wholeWords := range []bool{false, true} {
+			all, err := NewMatcher(patterns, MatcherOptions{WholeWords: wholeWords})
+			require.NoError(t, err)
+			var expected []Match
+			end := 0
+			for _, match := range all.FindAll(text) {
+				if match.Start >= end {
+					expected = append(expected, match)
+					end = match.End
+				}
+			}
+
+			m, err := NewMatcher(patterns, MatcherOptions{WholeWords: wholeWords, LeftmostLongest: true})
+			require.NoError(t, err)
+			require.Equal(t, expected, m.FindAll(text), "%q in %q", patterns, text)
+		}
+	}
+}
+
+func TestMatcherReplaceAll(t *testing.T) {
+	m, err := NewMatcher([]string{"darn", "darned", "heck"}, MatcherOptions{IgnoreCase: true, WholeWords: true})
+	require.NoError(t, err)
+
+	masked := m.ReplaceAll("Darned cat! What the heck, darn it. Darnedest.", func(match Match) string {
+		return strings.Repeat("*", match.End-match.Start)
+	})
+	require.Equal(t, "****** cat! What the ****, **** it. Darnedest.", masked)
+}
+
+// Compare to one regular expression per pattern, on the book.
+func TestMatcherSherlock(t *testing.T) {
+	data, err := os.ReadFile("testdata/sherlock.txt")
+	require.NoError(t, err)
+	text := strings.ToLower(string(data))
+	patterns := []string{"sherlock holmes", "baker street", "watson", "the", "irene adler", "adler"}
+	m, err := NewMatcher(patterns, MatcherOptions{WholeWords: true})
+	require.NoError(t, err)
+
+	counts := make([]int, len(patterns))
+	for _, match := range m.FindAll(text) {
+		counts[match.Pattern]++
+	}
+	for i, p := range patterns {
+		re := regexp.MustCompile(`\b` + regexp.QuoteMeta(p) + `\b`)
+		require.Equal(t, len(re.FindAllStringIndex(text, -1)), counts[i], p)
+	}
+}
+
+func ExampleMatcher_FindAll() {
+	m, _ := NewMatcher([]string{"Sherlock", "Sherlock Holmes", "Baker Street"}, MatcherOptions{IgnoreCase: true, LeftmostLongest: true})
+
+	text := "SHERLOCK HOLMES lived in Baker Street."
+	for _, match := range m.FindAll(text) {
+		fmt.Println(match.Pattern, text[match.Start:match.End])
+	}
+
+	// Output:
+	// 1 SHERLOCK HOLMES
+	// 2 Baker Street
+}
@@ -0,0 +1,83 @@
+package nlp
+
+import (
+	"regexp"
+	"strings"
+
+	"nlp/stemmer"
+)
+
+/*
+Analyzers.
+Tokenize does a fixed set of steps: find the words, lower case them, and stem them.
+An Analyzer lets you pick the steps:
+	- Char filters work on the raw text, before it is split into words (e.g. Unicode normalization).
+	- Filters work on the tokens, in order (e.g. lower case, stemming).
+*/
+
+// CharFilter transforms text before it is split into tokens.
+type CharFilter func(text string) string
+
+// Filter transforms a list of tokens.
+type Filter func(tokens []string) []string
+
+// Analyzer is a configurable tokenizer.
+type Analyzer struct {
+	CharFilters []CharFilter
+	Filters     []Filter
+	Pattern     *regexp.Regexp // Matches the tokens. Letters in any script if nil, use NumberTokenRe to keep numbers.
+}
+
+var (
+	// Unlike wordRe, letterRe matches letters in any script, and keeps combining marks with their letter.
+	letterRe = regexp.MustCompile(`\p{L}[\p{L}\p{M}]*`)
+
+	// DefaultAnalyzer tokenizes like Tokenize, but also keeps non-ASCII letters.
+	DefaultAnalyzer = Analyzer{
+		Filters: []Filter{LowerCaseFilter, StemFilter},
+	}
+)
+
+// Tokenize returns the tokens in text. Empty tokens are dropped.
+func (a Analyzer) Tokenize(text string) []string {
+	for _, cf := range a.CharFilters {
+		text = cf(text)
+	}
+
+	pattern := a.Pattern
+	if pattern == nil {
+		pattern = letterRe
+	}
+	tokens := pattern.FindAllString(text, -1)
+	for _, f := range a.Filters {
+		tokens = f(tokens)
+	}
+
+	out := tokens[:0]
+	for _, tok := range tokens {
+		if tok != "" {
+			out = append(out, tok)
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// MapFilter returns a Filter that applies fn to every token.
+func MapFilter(fn func(string) string) Filter {
+	return func(tokens []string) []string {
+		for i, tok := range tokens {
+			tokens[i] = fn(tok)
+		}
+		return tokens
+	}
+}
+
+var (
+	// LowerCaseFilter lower cases tokens.
+	LowerCaseFilter = MapFilter(strings.ToLower)
+	// StemFilter stems tokens with stemmer.Stem.
+	StemFilter = MapFilter(stemmer.Stem)
+)
@@ -0,0 +1,307 @@
+package nlp
+
+import (
+	"container/heap"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+/*
+Autocomplete.
+Sorted alphabetically, the words that start with a prefix are next to each other: a binary search finds their range.
+The words are also in a compact trie (a radix tree) that is built over the sorted list:
+a node is a range of words with a common prefix, its children split the range by the next byte,
+and there's no node for a prefix with only one continuation, so there are less nodes than words.
+The leaves are the words themselves, they take no memory. Every node stores the index of its most frequent word.
+To get the top k, start from the node of the prefix, and always expand the node with the most frequent word (a best first search):
+the words come out most frequent first, after looking at about k*depth nodes, whatever the number of words.
+On top of the words, that's 20 bytes per node and 4 per child, about 16 bytes per word for English
+(a sparse table for range maximum queries would take 4*log2(n) bytes per word, 68 for 100,000 words).
+
+Typos are handled by trying every prefix at one edit (a letter deleted, inserted, replaced, or two letters swapped).
+*/
+
+// Autocomplete completes prefixes with the most frequent words of a vocabulary.
+type Autocomplete struct {
+	words    []string   // Sorted.
+	counts   []int      // Count of words[i].
+	nodes    []trieNode // Inner nodes of the trie.
+	children []int32    // Children of the inner nodes, see trieNode.
+	root     int32
+	alphabet []rune // Runes in words, for typos.
+}
+
+// trieNode is an inner node of the trie, for the words in words[lo:hi] (at least two).
+// Its children are children[first:end]: a child c >= 0 is nodes[c], c < 0 is the leaf for words[-c-1].
+type trieNode struct {
+	lo, hi     int32
+	best       int32 // Index of the most frequent word.
+	first, end int32
+}
+
+// Completion is a completed word.
+type Completion struct {
+	Word  string `json:"word"`
+	Count int    `json:"count"`
+	Typo  bool   `json:"typo,omitempty"` // The prefix has a typo.
+}
+
+// minTypoPrefix is the minimal number of runes in a prefix for CompleteFuzzy to look for typos.
+const minTypoPrefix = 2
+
+// WordFrequencies counts the words (lower case) in text, like freq.go in module 4 does.
+func WordFrequencies(text string) map[string]int {
+	freq := make(map[string]int)
+	for _, w := range wordRe.FindAllString(text, -1) {
+		freq[strings.ToLower(w)]++
+	}
+	return freq
+}
+
+// NewAutocomplete returns an Autocomplete for the words in freq, e.g. from WordFrequencies.
+func NewAutocomplete(freq map[string]int) *Autocomplete {
+	a := &Autocomplete{words: make([]string, 0, len(freq))}
+	for w := range freq {
+		if w != "" {
+			a.words = append(a.words, w)
+		}
+	}
+	sort.Strings(a.words)
+
+	runes := make(map[rune]bool)
+	a.counts = make([]int, len(a.words))
+	for i, w := range a.words {
+		a.counts[i] = freq[w]
+		for _, r := range w {
+			runes[r] = true
+		}
+	}
+	for r := range runes {
+		a.alphabet = append(a.alphabet, r)
+	}
+	sort.Slice(a.alphabet, func(i, j int) bool { return a.alphabet[i] < a.alphabet[j] })
+
+	if len(a.words) > 0 {
+		a.root = a.build(0, len(a.words))
+	}
+	return a
+}
+
+// build adds the trie of words[lo:hi] and returns its root.
+func (a *Autocomplete) build(lo, hi int) int32 {
+	if hi-lo == 1 {
+		return leaf(lo)
+	}
+
+	// The words are sorted, their common prefix is the one of the first and the last.
+	first, last := a.words[lo], a.words[hi-1]
+	depth := 0
+	for depth < len(first) && first[depth] == last[depth] {
+		depth++
+	}
+
+	// Split by the byte after the prefix, the prefix itself (if it's a word) comes first.
+	bounds := []int{lo}
+	i := lo
+	if len(first) == depth {
+		i++
+		bounds = append(bounds, i)
+	}
+	for i < hi {
+		b := a.words[i][depth]
+		i += sort.Search(hi-i, func(j int) bool { return a.words[i+j][depth] != b })
+		bounds = append(bounds, i)
+	}
+
+	// Reserve the children first, so they are next to each other.
+	n := int32(len(a.nodes))
+	start := int32(len(a.children))
+	a.children = append(a.children, make([]int32, len(bounds)-1)...)
+	a.nodes = append(a.nodes, trieNode{lo: int32(lo), hi: int32(hi), best: int32(lo), first: start, end: int32(len(a.children))})
+	for j := range len(bounds) - 1 {
+		child := a.build(bounds[j], bounds[j+1])
+		a.children[start+int32(j)] = child
+		a.nodes[n].best = a.best(a.nodes[n].best, a.bestOf(child))
+	}
+	return n
+}
+
+// leaf returns the trie node of words[i].
+func leaf(i int) int32 {
+	return -int32(i) - 1
+}
+
+// bestOf returns the index of the most frequent word of a trie node.
+func (a *Autocomplete) bestOf(n int32) int32 {
+	if n < 0 {
+		return -n - 1
+	}
+	return a.nodes[n].best
+}
+
+// span returns the range of words of a trie node.
+func (a *Autocomplete) span(n int32) (int, int) {
+	if n < 0 {
+		return int(-n - 1), int(-n)
+	}
+	return int(a.nodes[n].lo), int(a.nodes[n].hi)
+}
+
+// node returns the trie node for the words in words[lo:hi], which must be the range of a prefix.
+func (a *Autocomplete) node(lo, hi int) int32 {
+	n := a.root
+	for {
+		if nlo, nhi := a.span(n); nlo == lo && nhi == hi {
+			return n
+		}
+		// Go down to the child with words[lo], the ranges of the nodes on the way all contain [lo, hi).
+		children := a.children[a.nodes[n].first:a.nodes[n].end]
+		i := sort.Search(len(children), func(i int) bool {
+			_, chi := a.span(children[i])
+			return chi > lo
+		})
+		n = children[i]
+	}
+}
+
+// Len returns the number of words.
+func (a *Autocomplete) Len() int {
+	return len(a.words)
+}
+
+// best returns the index of the most frequent word, the first in alphabetical order for a tie.
+func (a *Autocomplete) best(i, j int32) int32 {
+	if a.counts[j] > a.counts[i] || (a.counts[j] == a.counts[i] && j < i) {
+		return j
+	}
+	return i
+}
+
+// prefixRange returns the range of the words that start with prefix.
+func (a *Autocomplete) prefixRange(prefix string) (int, int) {
+	lo := sort.SearchStrings(a.words, prefix)
+	hi := lo + sort.Search(len(a.words)-lo, func(i int) bool {
+		return !strings.HasPrefix(a.words[lo+i], prefix)
+	})
+	return lo, hi
+}
+
+// Complete returns the k most frequent words that start with prefix (case sensitive), most frequent first.
+func (a *Autocomplete) Complete(prefix string, k int) []Completion {
+	lo, hi := a.prefixRange(prefix)
+	if lo == hi {
+		return nil
+	}
+	return a.top([]completion{{a.node(lo, hi), false}}, k)
+}
+
+// CompleteFuzzy is like Complete, but if there are less than k completions, it adds the completions
+// of the prefixes with one typo (for prefixes of 2 runes or more), ranked by frequency too.
+func (a *Autocomplete) CompleteFuzzy(prefix string, k int) []Completion {
+	completions := a.Complete(prefix, k)
+	if len(completions) >= k || utf8.RuneCountInString(prefix) < minTypoPrefix {
+		return completions
+	}
+
+	var nodes []completion
+	for _, p := range a.edits(prefix) {
+		if lo, hi := a.prefixRange(p); lo < hi {
+			nodes = append(nodes, completion{a.node(lo, hi), true})
+		}
+	}
+	seen := make(map[string]bool, len(completions))
+	for _, c := range completions {
+		seen[c.Word] = true
+	}
+	for _, c := range a.top(nodes, k+len(completions)) {
+		if len(completions) == k {
+			break
+		}
+		if !seen[c.Word] {
+			completions = append(completions, c)
+		}
+	}
+	return completions
+}
+
+// edits returns the strings at one edit from s, with runes from the alphabet.
+func (a *Autocomplete) edits(s string) []string {
+	runes := []rune(s)
+	seen := map[string]bool{s: true}
+	var edits []string
+	add := func(rs ...[]rune) {
+		var sb strings.Builder
+		for _, r := range rs {
+			sb.WriteString(string(r))
+		}
+		if e := sb.String(); !seen[e] {
+			seen[e] = true
+			edits = append(edits, e)
+		}
+	}
+
+	for i := range runes {
+		add(runes[:i], runes[i+1:]) // Deletion.
+		if i+1 < len(runes) {
+			add(runes[:i], []rune{runes[i+1], runes[i]}, runes[i+2:]) // Transposition.
+		}
+		for _, r := range a.alphabet {
+			add(runes[:i], []rune{r}, runes[i+1:]) // Substitution.
+			add(runes[:i], []rune{r}, runes[i:])   // Insertion, an insertion at the end is a completion.
+		}
+	}
+	return edits
+}
+
+// completion is a trie node, and whether it's for a prefix with a typo.
+type completion struct {
+	node int32
+	typo bool
+}
+
+// top returns the k most frequent words under nodes, which can overlap.
+func (a *Autocomplete) top(nodes []completion, k int) []Completion {
+	h := &nodeHeap{a: a, nodes: append([]completion(nil), nodes...)}
+	heap.Init(h)
+
+	var completions []Completion
+	seen := make(map[int32]bool)
+	for h.Len() > 0 && len(completions) < k {
+		c := heap.Pop(h).(completion)
+		if c.node < 0 {
+			if i := -c.node - 1; !seen[i] {
+				seen[i] = true
+				completions = append(completions, Completion{Word: a.words[i], Count: a.counts[i], Typo: c.typo})
+			}
+			continue
+		}
+		n := a.nodes[c.node]
+		for _, child := range a.children[n.first:n.end] {
+			heap.Push(h, completion{child, c.typo})
+		}
+	}
+	return completions
+}
+
+// nodeHeap is a max heap of trie nodes, by the count of their most frequent word.
+type nodeHeap struct {
+	a     *Autocomplete
+	nodes []completion
+}
+
+func (h *nodeHeap) Len() int { return len(h.nodes) }
+func (h *nodeHeap) Less(i, j int) bool {
+	bi, bj := h.a.bestOf(h.nodes[i].node), h.a.bestOf(h.nodes[j].node)
+	if bi == bj {
+		return h.nodes[i].node < 0 && h.nodes[j].node >= 0 // The leaf first, it's the word itself.
+	}
+	return h.a.best(bi, bj) == bi
+}
+func (h *nodeHeap) Swap(i, j int) { h.nodes[i], h.nodes[j] = h.nodes[j], h.nodes[i] }
+func (h *nodeHeap) Push(x any)    { h.nodes = append(h.nodes, x.(completion)) }
+func (h *nodeHeap) Pop() any {
+	n := h.nodes[len(h.nodes)-1]
+	h.nodes = h.nodes[:len(h.nodes)-1]
+	return n
+}
@@ -0,0 +1,128 @@
+package nlp
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutocomplete(t *testing.T) {
+	a := NewAutocomplete(map[string]int{
+		"holmes": 50, "hold": 20, "holiday": 5, "home": 30, "house": 25, "hollow": 5, "watson": 40,
+	})
+	require.Equal(t, 7, a.Len())
+
+	var cases = []struct {
+		prefix   string
+		k        int
+		expected []string
+	}{
+		{"hol", 2, []string{"holmes", "hold"}},
+		{"hol", 10, []string{"holmes", "hold", "holiday", "hollow"}}, // Ties in alphabetical order.
+		{"h", 3, []string{"holmes", "home", "house"}},
+		{"", 1, []string{"holmes"}},
+		{"watson", 5, []string{"watson"}},
+		{"x", 5, nil},
+		{"hol", 0, nil},
+	}
+	for _, tc := range cases {
+		t.Run(fmt.Sprintf("%s/%d", tc.prefix, tc.k), func(t *testing.T) {
+			var words []string
+			for _, c := range a.Complete(tc.prefix, tc.k) {
+				words = append(words, c.Word)
+				require.False(t, c.Typo)
+			}
+			require.Equal(t, tc.expected, words)
+		})
+	}
+}
+
+func TestAutocompletePrefixWords(t *testing.T) {
+	// Words that are prefixes of other words, and non ASCII letters.
+	a := NewAutocomplete(map[string]int{"a": 1, "ab": 3, "abc": 2, "abd": 5, "é": 4, "éa": 6})
+	var words []string
+	for _, c := range a.Complete("", 10) {
+		words = append(words, c.Word)
+	}
+	require.Equal(t, []string{"éa", "abd", "é", "ab", "abc", "a"}, words)
+	require.Equal(t, []Completion{{"abd", 5, false}, {"ab", 3, false}}, a.Complete("ab", 2))
+	require.Equal(t, []Completion{{"abc", 2, false}}, a.Complete("abc", 3))
+	require.Equal(t, []Completion{{"éa", 6, false}, {"é", 4, false}}, a.Complete("é", 3))
+
+	require.Nil(t, NewAutocomplete(nil).Complete("", 5))
+	require.Equal(t, []Completion{{"holmes", 1, false}}, NewAutocomplete(map[string]int{"holmes": 1}).Complete("ho", 5))
+}
+
+func TestAutocompleteFuzzy(t *testing.T) {
+	a := NewAutocomplete(map[string]int{"holmes": 50, "hold": 20, "watson": 40, "what": 60})
+
+	var cases = []struct {
+		prefix   string
+		expected []Completion
+	}{
+		{"wtas", []Completion{{"watson", 40, true}}},                     // Transposition.
+		{"hlm", []Completion{{"holmes", 50, true}}},                      // Insertion.
+		{"hpl", []Completion{{"holmes", 50, true}, {"hold", 20, true}}},  // Substitution.
+		{"wha", []Completion{{"what", 60, false}, {"watson", 40, true}}}, // Exact matches first.
+		{"q", nil}, // Too short.
+	}
+	for _, tc := range cases {
+		t.Run(tc.prefix, func(t *testing.T) {
+			require.Equal(t, tc.expected, a.CompleteFuzzy(tc.prefix, 3))
+		})
+	}
+}
+
+func TestAutocompleteSherlock(t *testing.T) {
+	data, err := os.ReadFile("testdata/sherlock.txt")
+	require.NoError(t, err)
+	freq := WordFrequencies(string(data))
+	a := NewAutocomplete(freq)
+
+	// Compare to sorting all the words.
+	for _, prefix := range []string{"s", "sh", "wat", "a", "th"} {
+		completions := a.Complete(prefix, 5)
+		require.Len(t, completions, 5)
+		for i, c := range completions {
+			require.Equal(t, freq[c.Word], c.Count)
+			if i > 0 {
+				require.GreaterOrEqual(t, completions[i-1].Count, c.Count)
+			}
+		}
+		for w, count := range freq {
+			if len(w) >= len(prefix) && w[:len(prefix)] == prefix && count > completions[4].Count {
+				require.Contains(t, completions, Completion{Word: w, Count: count}, prefix)
+			}
+		}
+	}
+	require.Equal(t, "sherlock", a.Complete("sher", 1)[0].Word)
+}
+
+// BenchmarkAutocomplete measures completions in a vocabulary of 100,000 words.
+func BenchmarkAutocomplete(b *testing.B) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	freq := make(map[string]int)
+	for len(freq) < 100_000 {
+		word := make([]byte, 3+rng.IntN(8))
+		for i := range word {
+			word[i] = byte('a' + rng.IntN(26))
+		}
+		freq[string(word)] = 1 + int(1000/(1+rng.Float64()*999))
+	}
+	a := NewAutocomplete(freq)
+	prefixes := []string{"a", "th", "qu", "hel", "wor", "zzz"}
+
+	b.Run("exact", func(b *testing.B) {
+		for i := range b.N {
+			a.Complete(prefixes[i%len(prefixes)], 10)
+		}
+	})
+	b.Run("fuzzy", func(b *testing.B) {
+		for i := range b.N {
+			a.CompleteFuzzy(prefixes[i%len(prefixes)]+"x", 10)
+		}
+	})
+}
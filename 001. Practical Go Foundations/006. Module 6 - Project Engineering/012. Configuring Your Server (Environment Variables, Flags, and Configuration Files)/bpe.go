@@ -0,0 +1,341 @@
+package nlp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+/*
+Byte pair encoding (BPE).
+Instead of words, BPE splits text into subwords: frequent words ("the") are a single token,
+and rare words are split into pieces ("Moriarty" -> "Mor" "iar" "ty").
+It starts from the 256 byte values, and TrainBPE learns merges: the most frequent pair of adjacent tokens in the corpus
+becomes a new token, over and over until the vocabulary has the requested size.
+Since every byte is a token, any text can be encoded, and decoding gives back exactly the original text.
+*/
+
+// pretokenRe splits text into chunks that merges never cross: words with their leading space, numbers, punctuation, and spaces.
+var pretokenRe = regexp.MustCompile(`'(?:s|t|re|ve|m|ll|d)| ?\p{L}+| ?\p{N}+| ?[^\s\p{L}\p{N}]+|\s+`)
+
+// byteTokens is the size of the base vocabulary, one token per byte value.
+const byteTokens = 256
+
+// Merge is a BPE merge rule: the adjacent tokens Left and Right become the token Left+Right.
+type Merge struct {
+	Left  string
+	Right string
+}
+
+// BPE is a byte pair encoding tokenizer.
+type BPE struct {
+	vocab  []string        // ID -> token.
+	ids    map[string]int  // Token -> ID.
+	bytes  [byteTokens]int // Byte value -> ID of its token.
+	merges []Merge
+	ranks  map[[2]int]int // Pair of IDs -> index in merges, lower merges first.
+}
+
+// TrainBPE learns a BPE vocabulary of up to vocabSize tokens (including the 256 byte tokens) from the text in r.
+// Training stops early when no pair of tokens occurs more than once.
+func TrainBPE(r io.Reader, vocabSize int) (*BPE, error) {
+	if vocabSize < byteTokens {
+		return nil, fmt.Errorf("vocabulary size must be at least %d, got %d", byteTokens, vocabSize)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Count the chunks, every occurrence of a chunk is merged the same way.
+	counts := make(map[string]int)
+	for _, chunk := range pretokenize(string(data)) {
+		counts[chunk]++
+	}
+	type word struct {
+		ids   []int
+		count int
+	}
+	words := make([]word, 0, len(counts))
+	b := newBPE()
+	for chunk, count := range counts {
+		words = append(words, word{b.byteIDs(chunk), count})
+	}
+
+	for len(b.vocab) < vocabSize {
+		pairs := make(map[[2]int]int)
+		for _, w := range words {
+			for i := 0; i+1 < len(w.ids); i++ {
+				pairs[[2]int{w.ids[i], w.ids[i+1]}] += w.count
+			}
+		}
+
+		// Pick the most frequent pair, ties go to the lowest IDs so training is deterministic.
+		best, bestCount := [2]int{}, 1
+		for pair, count := range pairs {
+			if count > bestCount || (count == bestCount && bestCount > 1 && lessPair(pair, best)) {
+				best, bestCount = pair, count
+			}
+		}
+		if bestCount < 2 {
+			break
+		}
+
+		id := b.addMerge(Merge{b.vocab[best[0]], b.vocab[best[1]]})
+		for i := range words {
+			words[i].ids = mergePair(words[i].ids, best, id)
+		}
+	}
+	return b, nil
+}
+
+func newBPE() *BPE {
+	b := &BPE{
+		ids:   make(map[string]int),
+		ranks: make(map[[2]int]int),
+	}
+	for i := range byteTokens {
+		b.bytes[i] = b.addToken(string([]byte{byte(i)}))
+	}
+	return b
+}
+
+func (b *BPE) addToken(tok string) int {
+	if id, ok := b.ids[tok]; ok {
+		return id
+	}
+	b.ids[tok] = len(b.vocab)
+	b.vocab = append(b.vocab, tok)
+	return len(b.vocab) - 1
+}
+
+// addMerge adds m to the merges, and its result to the vocabulary. It returns the ID of the result.
+func (b *BPE) addMerge(m Merge) int {
+	pair := [2]int{b.ids[m.Left], b.ids[m.Right]}
+	b.ranks[pair] = len(b.merges)
+	b.merges = append(b.merges, m)
+	return b.addToken(m.Left + m.Right)
+}
+
+// VocabSize returns the number of tokens in the vocabulary.
+func (b *BPE) VocabSize() int {
+	return len(b.vocab)
+}
+
+// Merges returns the merge rules, in the order they are applied.
+func (b *BPE) Merges() []Merge {
+	return append([]Merge(nil), b.merges...)
+}
+
+// Token returns the token for id, and false if there is no such token.
+func (b *BPE) Token(id int) (string, bool) {
+	if id < 0 || id >= len(b.vocab) {
+		return "", false
+	}
+	return b.vocab[id], true
+}
+
+// Encode returns the token IDs of text.
+func (b *BPE) Encode(text string) []int {
+	var ids []int
+	for _, chunk := range pretokenize(text) {
+		ids = append(ids, b.encodeChunk(chunk)...)
+	}
+	return ids
+}
+
+// Count returns the number of tokens in text, it's len(b.Encode(text)).
+func (b *BPE) Count(text string) int {
+	n := 0
+	for _, chunk := range pretokenize(text) {
+		n += len(b.encodeChunk(chunk))
+	}
+	return n
+}
+
+// encodeChunk applies the merges to chunk, lowest rank first, until none applies.
+func (b *BPE) encodeChunk(chunk string) []int {
+	ids := b.byteIDs(chunk)
+	for len(ids) > 1 {
+		best, bestRank := [2]int{}, -1
+		for i := 0; i+1 < len(ids); i++ {
+			pair := [2]int{ids[i], ids[i+1]}
+			if rank, ok := b.ranks[pair]; ok && (bestRank < 0 || rank < bestRank) {
+				best, bestRank = pair, rank
+			}
+		}
+		if bestRank < 0 {
+			break
+		}
+		m := b.merges[bestRank]
+		ids = mergePair(ids, best, b.ids[m.Left+m.Right])
+	}
+	return ids
+}
+
+// Decode returns the text for ids. It fails on IDs that are not in the vocabulary.
+func (b *BPE) Decode(ids []int) (string, error) {
+	var sb strings.Builder
+	for i, id := range ids {
+		tok, ok := b.Token(id)
+		if !ok {
+			return "", fmt.Errorf("%d: unknown token ID %d", i, id)
+		}
+		sb.WriteString(tok)
+	}
+	return sb.String(), nil
+}
+
+/*
+Save writes the vocabulary and the merges.
+The vocabulary has one token per line, the line number (from 0) is the token ID.
+The merges have one merge per line, the two tokens separated by a space.
+Tokens are written as Go quoted strings, so spaces, new lines and bytes that are not valid UTF-8 survive.
+*/
+func (b *BPE) Save(vocab, merges io.Writer) error {
+	vw := bufio.NewWriter(vocab)
+	for _, tok := range b.vocab {
+		fmt.Fprintln(vw, strconv.Quote(tok))
+	}
+	if err := vw.Flush(); err != nil {
+		return err
+	}
+
+	mw := bufio.NewWriter(merges)
+	fmt.Fprintln(mw, "# nlp BPE merges")
+	for _, m := range b.merges {
+		fmt.Fprintf(mw, "%s %s\n", strconv.Quote(m.Left), strconv.Quote(m.Right))
+	}
+	return mw.Flush()
+}
+
+// LoadBPE reads a vocabulary and merges written by Save.
+// The vocabulary must have all 256 single byte tokens, and the tokens of every merge and their result.
+func LoadBPE(vocab, merges io.Reader) (*BPE, error) {
+	b := &BPE{
+		ids:   make(map[string]int),
+		ranks: make(map[[2]int]int),
+	}
+
+	s := bufio.NewScanner(vocab)
+	lnum := 0
+	for s.Scan() {
+		lnum++
+		tok, err := strconv.Unquote(s.Text())
+		if err != nil {
+			return nil, fmt.Errorf("vocabulary %d: bad token %q", lnum, s.Text())
+		}
+		if _, ok := b.ids[tok]; ok {
+			return nil, fmt.Errorf("vocabulary %d: duplicate token %q", lnum, tok)
+		}
+		b.addToken(tok)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	for i := range byteTokens {
+		id, ok := b.ids[string([]byte{byte(i)})]
+		if !ok {
+			return nil, fmt.Errorf("vocabulary: missing byte token %q", string([]byte{byte(i)}))
+		}
+		b.bytes[i] = id
+	}
+
+	s = bufio.NewScanner(merges)
+	lnum = 0
+	for s.Scan() {
+		lnum++
+		line := s.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		m, err := parseMerge(line)
+		if err != nil {
+			return nil, fmt.Errorf("merges %d: %w", lnum, err)
+		}
+		for _, tok := range []string{m.Left, m.Right, m.Left + m.Right} {
+			if _, ok := b.ids[tok]; !ok {
+				return nil, fmt.Errorf("merges %d: %q not in vocabulary", lnum, tok)
+			}
+		}
+		pair := [2]int{b.ids[m.Left], b.ids[m.Right]}
+		if _, ok := b.ranks[pair]; ok {
+			return nil, fmt.Errorf("merges %d: duplicate merge %q %q", lnum, m.Left, m.Right)
+		}
+		b.ranks[pair] = len(b.merges)
+		b.merges = append(b.merges, m)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// parseMerge parses a merges line: two quoted tokens separated by a space.
+func parseMerge(line string) (Merge, error) {
+	left, err := strconv.QuotedPrefix(line)
+	if err != nil {
+		return Merge{}, fmt.Errorf("bad merge %q", line)
+	}
+	right, ok := strings.CutPrefix(line[len(left):], " ")
+	if !ok {
+		return Merge{}, fmt.Errorf("bad merge %q", line)
+	}
+
+	var m Merge
+	m.Left, _ = strconv.Unquote(left)
+	if m.Right, err = strconv.Unquote(right); err != nil {
+		return Merge{}, fmt.Errorf("bad merge %q", line)
+	}
+	return m, nil
+}
+
+// pretokenize splits text into chunks. The chunks always add up to text, so encoding is lossless.
+func pretokenize(text string) []string {
+	var chunks []string
+	pos := 0
+	for _, loc := range pretokenRe.FindAllStringIndex(text, -1) {
+		if loc[0] > pos {
+			chunks = append(chunks, text[pos:loc[0]]) // Shouldn't happen, the regular expression matches everything.
+		}
+		chunks = append(chunks, text[loc[0]:loc[1]])
+		pos = loc[1]
+	}
+	if pos < len(text) {
+		chunks = append(chunks, text[pos:])
+	}
+	return chunks
+}
+
+// byteIDs returns the IDs of the byte tokens of s.
+func (b *BPE) byteIDs(s string) []int {
+	ids := make([]int, len(s))
+	for i := range len(s) {
+		ids[i] = b.bytes[s[i]]
+	}
+	return ids
+}
+
+// mergePair replaces every occurrence of pair in ids with id, in place.
+func mergePair(ids []int, pair [2]int, id int) []int {
+	out := ids[:0]
+	for i := 0; i < len(ids); i++ {
+		if i+1 < len(ids) && ids[i] == pair[0] && ids[i+1] == pair[1] {
+			out = append(out, id)
+			i++
+			continue
+		}
+		out = append(out, ids[i])
+	}
+	return out
+}
+
+func lessPair(a, b [2]int) bool {
+	if a[0] != b[0] {
+		return a[0] < b[0]
+	}
+	return a[1] < b[1]
+}
@@ -0,0 +1,81 @@
+package nlp
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func trainSherlock(t *testing.T, size int) *BPE {
+	t.Helper()
+	file, err := os.Open("testdata/sherlock.txt")
+	require.NoError(t, err)
+	defer file.Close()
+
+	b, err := TrainBPE(NewReader(file), size)
+	require.NoError(t, err)
+	return b
+}
+
+func TestBPE(t *testing.T) {
+	b := trainSherlock(t, 1000)
+	require.Equal(t, 1000, b.VocabSize())
+
+	text := "Sherlock Holmes took his bottle from the corner of the mantelpiece."
+	ids := b.Encode(text)
+	require.Less(t, len(ids), len(text)/2) // Common words are single tokens.
+	require.Equal(t, len(ids), b.Count(text))
+	for _, word := range []string{" the", " Holmes"} {
+		_, ok := b.ids[word]
+		require.True(t, ok, word)
+	}
+
+	// Decoding is lossless, even for text that is nothing like the corpus.
+	for _, text := range []string{text, "", "  spaces\tand\r\nnew lines  ", "Größe, 東京, 🙂", "bad \xff utf-8"} {
+		decoded, err := b.Decode(b.Encode(text))
+		require.NoError(t, err)
+		require.Equal(t, text, decoded)
+	}
+
+	_, err := b.Decode([]int{1, b.VocabSize()})
+	require.Error(t, err)
+}
+
+func TestBPESaveLoad(t *testing.T) {
+	b, err := TrainBPE(strings.NewReader("low lower lowest newer newest wider widest"), 300)
+	require.NoError(t, err)
+
+	var vocab, merges bytes.Buffer
+	err = b.Save(&vocab, &merges)
+	require.NoError(t, err)
+
+	b2, err := LoadBPE(&vocab, &merges)
+	require.NoError(t, err)
+	require.Equal(t, b.VocabSize(), b2.VocabSize())
+	require.Equal(t, b.Merges(), b2.Merges())
+
+	text := "the lowest and the newest\n"
+	require.Equal(t, b.Encode(text), b2.Encode(text))
+
+	_, err = LoadBPE(strings.NewReader(`"a"`+"\n"), strings.NewReader(""))
+	require.Error(t, err) // Missing byte tokens.
+
+	vocab.Reset()
+	merges.Reset()
+	b.Save(&vocab, &merges)
+	_, err = LoadBPE(&vocab, strings.NewReader(`"zz" "q"`+"\n"))
+	require.Error(t, err) // Merge not in the vocabulary.
+}
+
+func TestTrainBPESize(t *testing.T) {
+	_, err := TrainBPE(strings.NewReader("abc"), 100)
+	require.Error(t, err)
+
+	// Training stops when there is nothing left to merge.
+	b, err := TrainBPE(strings.NewReader("ab ab ab"), 1000)
+	require.NoError(t, err)
+	require.Equal(t, []Merge{{"a", "b"}, {" ", "ab"}}, b.Merges())
+}
@@ -0,0 +1,168 @@
+package nlp
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+)
+
+/*
+Chunking.
+Retrieval systems embed documents in pieces: a chunk must fit the embedding model, and should be about one thing.
+ChunkText fills chunks word by word up to the token budget. When the next word doesn't fit, the chunk ends at
+the last paragraph break, or else at the last sentence end, as long as that keeps at least half of the budget.
+Consecutive chunks overlap, so a sentence cut by a chunk boundary is still whole in one of them:
+the next chunk starts with the last words of the previous one, from the start of a sentence if possible.
+*/
+
+var (
+	// chunkWordRe matches the units of chunks: anything between spaces.
+	chunkWordRe = regexp.MustCompile(`\S+`)
+	// paragraphRe matches paragraph breaks: an empty line.
+	paragraphRe = regexp.MustCompile(`\n[ \t\r]*\n`)
+)
+
+// ChunkOptions are the options of ChunkText.
+type ChunkOptions struct {
+	MaxTokens int // Maximum number of tokens in a chunk, must be positive.
+	Overlap   int // Number of tokens shared by consecutive chunks, must be less than MaxTokens.
+	// Count returns the number of tokens in a piece of text, e.g. (*BPE).Count.
+	// It's called on each word with the spaces before it, and the counts are added. The default is len(Tokenize(s)).
+	Count func(s string) int
+}
+
+// Chunk is a part of a text.
+type Chunk struct {
+	Text   string `json:"text"`
+	Start  int    `json:"start"` // Byte offsets in the text.
+	End    int    `json:"end"`
+	Tokens int    `json:"tokens"`
+}
+
+// boundary is what comes after a word.
+type boundary int
+
+const (
+	noBoundary boundary = iota
+	sentenceBoundary
+	paragraphBoundary
+)
+
+// chunkWord is a word of the text.
+type chunkWord struct {
+	start, end int
+	tokens     int
+	after      boundary
+}
+
+// ChunkText splits text into chunks of at most opts.MaxTokens tokens.
+// A word with more tokens than that is a chunk on its own.
+func ChunkText(text string, opts ChunkOptions) ([]Chunk, error) {
+	if opts.MaxTokens < 1 {
+		return nil, errors.New("MaxTokens must be positive")
+	}
+	if opts.Overlap < 0 || opts.Overlap >= opts.MaxTokens {
+		return nil, errors.New("Overlap must be between 0 and MaxTokens-1")
+	}
+	count := opts.Count
+	if count == nil {
+		count = func(s string) int { return len(Tokenize(s)) }
+	}
+
+	words := chunkWords(text, count)
+	var chunks []Chunk
+	prevEnd := 0 // Index of the word after the previous chunk.
+	for start := 0; start < len(words); {
+		// Fill the chunk.
+		end, tokens := start, 0
+		for end < len(words) && (end == start || tokens+words[end].tokens <= opts.MaxTokens) {
+			tokens += words[end].tokens
+			end++
+		}
+		if end < len(words) {
+			end = chunkCut(words, start, end, prevEnd, opts.MaxTokens/2)
+		}
+
+		tokens = 0
+		for _, w := range words[start:end] {
+			tokens += w.tokens
+		}
+		chunks = append(chunks, Chunk{
+			Text:   text[words[start].start:words[end-1].end],
+			Start:  words[start].start,
+			End:    words[end-1].end,
+			Tokens: tokens,
+		})
+		if end == len(words) {
+			break
+		}
+		start, prevEnd = chunkOverlap(words, start, end, opts), end
+	}
+	return chunks, nil
+}
+
+// chunkWords returns the words of text, with their tokens and what comes after them.
+func chunkWords(text string, count func(string) int) []chunkWord {
+	locs := chunkWordRe.FindAllStringIndex(text, -1)
+	words := make([]chunkWord, len(locs))
+	prev := 0
+	for i, loc := range locs {
+		words[i] = chunkWord{start: loc[0], end: loc[1], tokens: count(text[prev:loc[1]])}
+		prev = loc[1]
+	}
+
+	// Mark the word before every sentence and paragraph end.
+	mark := func(offset int, b boundary) {
+		// The last word that starts before offset.
+		i := sort.Search(len(words), func(i int) bool { return words[i].start >= offset }) - 1
+		if i >= 0 && words[i].after < b {
+			words[i].after = b
+		}
+	}
+	for _, span := range sentenceSpans(text) {
+		mark(span[1], sentenceBoundary)
+	}
+	for _, loc := range paragraphRe.FindAllStringIndex(text, -1) {
+		mark(loc[0]+1, paragraphBoundary)
+	}
+	return words
+}
+
+// chunkCut returns where to end the chunk words[start:end], which is full: after the last paragraph break, else after
+// the last sentence end, if the chunk keeps at least minTokens tokens and goes beyond the previous chunk, else at end.
+func chunkCut(words []chunkWord, start, end, prevEnd, minTokens int) int {
+	for _, b := range []boundary{paragraphBoundary, sentenceBoundary} {
+		cut := -1
+		tokens := 0 // Tokens in words[start:i+1].
+		for i := start; i < end; i++ {
+			tokens += words[i].tokens
+			if words[i].after >= b && tokens >= minTokens && i+1 > prevEnd {
+				cut = i + 1
+			}
+		}
+		if cut != -1 {
+			return cut
+		}
+	}
+	return end
+}
+
+// chunkOverlap returns where the chunk after words[start:end] starts: up to opts.Overlap tokens before end,
+// at the start of a sentence if one is in the overlap.
+func chunkOverlap(words []chunkWord, start, end int, opts ChunkOptions) int {
+	next, tokens := end, 0
+	for next-1 > start && tokens+words[next-1].tokens <= opts.Overlap {
+		tokens += words[next-1].tokens
+		next--
+	}
+	// The next chunk must get past this one.
+	if tokens+words[end].tokens > opts.MaxTokens {
+		return end
+	}
+	for i := next; i < end; i++ {
+		if words[i-1].after != noBoundary {
+			return i
+		}
+	}
+	return next
+}
@@ -0,0 +1,95 @@
+package nlp
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/stretchr/testify/require"
+)
+
+// wordCount counts words, so the tests don't depend on stop words or stemming.
+func wordCount(s string) int {
+	return len(strings.Fields(s))
+}
+
+func chunkTexts(chunks []Chunk) []string {
+	var texts []string
+	for _, c := range chunks {
+		texts = append(texts, c.Text)
+	}
+	return texts
+}
+
+func TestChunkText(t *testing.T) {
+	text := "One two. Three four.\n\nFive six. Seven eight nine."
+	chunks, err := ChunkText(text, ChunkOptions{MaxTokens: 6, Count: wordCount})
+	require.NoError(t, err)
+
+	for _, c := range chunks {
+		require.Equal(t, c.Text, text[c.Start:c.End])
+		require.Equal(t, wordCount(c.Text), c.Tokens)
+		require.LessOrEqual(t, c.Tokens, 6)
+	}
+	// "Five six." fits in the first chunk, but it ends at the paragraph.
+	require.Equal(t, []string{"One two. Three four.", "Five six. Seven eight nine."}, chunkTexts(chunks))
+
+	chunks, err = ChunkText("One two three. Four five six seven eight.", ChunkOptions{MaxTokens: 6, Count: wordCount})
+	require.NoError(t, err)
+	require.Equal(t, []string{"One two three.", "Four five six seven eight."}, chunkTexts(chunks))
+}
+
+func TestChunkTextOverlap(t *testing.T) {
+	text := "Aa bb cc. Dd ee ff gg hh ii jj kk." // Not "A b c.", "c." would be an initial.
+	chunks, err := ChunkText(text, ChunkOptions{MaxTokens: 6, Overlap: 4, Count: wordCount})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"Aa bb cc.",
+		"bb cc. Dd ee ff gg",
+		"Dd ee ff gg hh ii", // The overlap starts at the sentence.
+		"ff gg hh ii jj kk.",
+	}, chunkTexts(chunks))
+}
+
+func TestChunkTextLongWord(t *testing.T) {
+	count := func(s string) int { return len(strings.TrimSpace(s)) } // Letters.
+	chunks, err := ChunkText("a bbbbbb c", ChunkOptions{MaxTokens: 3, Overlap: 1, Count: count})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "bbbbbb", "c"}, chunkTexts(chunks))
+}
+
+func TestChunkTextTokenize(t *testing.T) {
+	data, err := os.ReadFile("testdata/sherlock.txt")
+	require.NoError(t, err)
+	text := string(data)
+
+	chunks, err := ChunkText(text, ChunkOptions{MaxTokens: 100, Overlap: 20})
+	require.NoError(t, err)
+	require.NotEmpty(t, chunks)
+	require.Equal(t, len(text)-len(strings.TrimLeftFunc(text, unicode.IsSpace)), chunks[0].Start)
+	for i, c := range chunks {
+		require.LessOrEqual(t, c.Tokens, 100)
+		require.Equal(t, c.Text, text[c.Start:c.End])
+		if i > 0 {
+			prev := chunks[i-1]
+			require.Greater(t, c.Start, prev.Start)
+			require.Greater(t, c.End, prev.End)
+			if c.Start > prev.End { // No words between chunks.
+				require.Empty(t, strings.TrimSpace(text[prev.End:c.Start]))
+			}
+		}
+	}
+	require.Equal(t, len(strings.TrimRightFunc(text, unicode.IsSpace)), chunks[len(chunks)-1].End)
+}
+
+func TestChunkTextErrors(t *testing.T) {
+	for _, opts := range []ChunkOptions{{}, {MaxTokens: -1}, {MaxTokens: 5, Overlap: 5}, {MaxTokens: 5, Overlap: -1}} {
+		_, err := ChunkText("text", opts)
+		require.Error(t, err, "%+v", opts)
+	}
+
+	chunks, err := ChunkText(" \n ", ChunkOptions{MaxTokens: 5})
+	require.NoError(t, err)
+	require.Empty(t, chunks)
+}
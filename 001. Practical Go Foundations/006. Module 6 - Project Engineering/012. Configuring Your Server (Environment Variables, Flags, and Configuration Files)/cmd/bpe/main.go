@@ -0,0 +1,76 @@
+/*
+bpe trains a byte pair encoding vocabulary, to use with the /encode and /decode routes of cmd/httpd.
+
+	go run ./cmd/bpe -size 1000 -out sherlock testdata/sherlock.txt
+
+writes sherlock.vocab and sherlock.merges, then run the server with:
+
+	go run ./cmd/httpd -bpe sherlock
+*/
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"nlp"
+)
+
+func main() {
+	size := flag.Int("size", 1000, "Vocabulary size (at least 256)")
+	out := flag.String("out", "bpe", "Prefix of the output files (<out>.vocab and <out>.merges)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] FILE...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(flag.Args(), *size, *out); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(paths []string, size int, out string) error {
+	readers := make([]io.Reader, 0, len(paths))
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		readers = append(readers, nlp.NewReader(file))
+	}
+
+	b, err := nlp.TrainBPE(io.MultiReader(readers...), size)
+	if err != nil {
+		return err
+	}
+
+	vocab, err := os.Create(out + ".vocab")
+	if err != nil {
+		return err
+	}
+	defer vocab.Close()
+
+	merges, err := os.Create(out + ".merges")
+	if err != nil {
+		return err
+	}
+	defer merges.Close()
+
+	if err := b.Save(vocab, merges); err != nil {
+		return err
+	}
+	if err := vocab.Close(); err != nil {
+		return err
+	}
+	return merges.Close()
+}
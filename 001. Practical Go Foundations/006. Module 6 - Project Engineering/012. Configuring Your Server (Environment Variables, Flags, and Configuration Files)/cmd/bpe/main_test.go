@@ -0,0 +1,53 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"nlp"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_run(t *testing.T) {
+	dir := t.TempDir()
+	corpus := filepath.Join(dir, "corpus.txt")
+	out := filepath.Join(dir, "corpus")
+	text := "the cat sat on the mat, the cat sat on the hat, the cat sat."
+	require.NoError(t, os.WriteFile(corpus, []byte(text), 0o644))
+
+	require.NoError(t, run([]string{corpus}, 260, out))
+
+	// One quoted token per line, and a header followed by one merge per line.
+	data, err := os.ReadFile(out + ".vocab")
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	require.Len(t, lines, 260)
+	require.Equal(t, `"\x00"`, lines[0])
+	data, err = os.ReadFile(out + ".merges")
+	require.NoError(t, err)
+	lines = strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	require.Equal(t, "# nlp BPE merges", lines[0])
+	require.Len(t, lines, 1+4)
+
+	vocab, err := os.Open(out + ".vocab")
+	require.NoError(t, err)
+	defer vocab.Close()
+	merges, err := os.Open(out + ".merges")
+	require.NoError(t, err)
+	defer merges.Close()
+
+	b, err := nlp.LoadBPE(vocab, merges)
+	require.NoError(t, err)
+	require.Equal(t, 260, b.VocabSize())
+	require.Len(t, b.Merges(), 4)
+	require.Less(t, len(b.Encode(text)), len(text))
+
+	decoded, err := b.Decode(b.Encode(text))
+	require.NoError(t, err)
+	require.Equal(t, text, decoded)
+
+	require.Error(t, run([]string{filepath.Join(dir, "missing.txt")}, 260, out))
+}
@@ -0,0 +1,60 @@
+/*
+concordance prints every occurrence of a word in text files, with its context (keyword in context).
+
+	go run ./cmd/concordance -width 6 -sort right detecting testdata/sherlock.txt
+
+Words are matched by their stem, so "detecting" also finds "detected" and "detects".
+*/
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"nlp"
+)
+
+func main() {
+	width := flag.Int("width", 5, "Number of words of context on each side")
+	order := flag.String("sort", "text", "Sort lines by context: text, left or right")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] WORD FILE...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() < 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	kwicOrder, err := nlp.ParseKWICOrder(*order)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		os.Exit(2)
+	}
+
+	if err := run(os.Stdout, flag.Arg(0), flag.Args()[1:], *width, kwicOrder); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(w io.Writer, query string, paths []string, width int, order nlp.KWICOrder) error {
+	var lines []nlp.KWICLine
+	for _, path := range paths {
+		text, err := nlp.ReadFileUTF8(path)
+		if err != nil {
+			return err
+		}
+		lines = append(lines, nlp.NewConcordance(text).Find(query, width)...)
+	}
+
+	// Offsets are per file, keep the files in order.
+	if order != nlp.TextOrder {
+		nlp.SortKWIC(lines, order)
+	}
+	return nlp.WriteKWIC(w, lines)
+}
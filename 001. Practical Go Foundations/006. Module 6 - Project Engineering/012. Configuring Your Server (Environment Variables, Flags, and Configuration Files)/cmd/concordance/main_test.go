@@ -0,0 +1,28 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"nlp"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_run(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.txt")
+	b := filepath.Join(dir, "b.txt")
+	require.NoError(t, os.WriteFile(a, []byte("He was detecting crimes."), 0o644))
+	require.NoError(t, os.WriteFile(b, []byte("\xef\xbb\xbfShe detected a smell."), 0o644)) // With a BOM.
+
+	var buf bytes.Buffer
+	err := run(&buf, "detect", []string{a, b}, 1, nlp.RightOrder)
+	require.NoError(t, err)
+	require.Equal(t, "She  detected   a\nwas  detecting  crimes\n", buf.String())
+
+	err = run(&buf, "detect", []string{filepath.Join(dir, "missing.txt")}, 1, nlp.TextOrder)
+	require.Error(t, err)
+}
@@ -0,0 +1,186 @@
+/*
+corpus prints statistics of a corpus: token and type counts, hapax legomena, Zipf's and Heaps' law fits, etc.
+
+	go run ./cmd/corpus testdata/sherlock.txt
+	go run ./cmd/corpus -each -format csv docs/
+
+Directories are read recursively, only the files with the -ext extension are used.
+*/
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"nlp"
+)
+
+func main() {
+	format := flag.String("format", "text", "Output format: text, json or csv")
+	each := flag.Bool("each", false, "Also report every file")
+	top := flag.Int("top", 10, "Number of most frequent words to report")
+	ext := flag.String("ext", ".txt", "Extension of the files to read in directories")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] FILE|DIR...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	write, ok := writers[*format]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "error: unknown format: %q\n", *format)
+		os.Exit(2)
+	}
+
+	reports, err := run(flag.Args(), *ext, *top, *each)
+	if err == nil {
+		err = write(os.Stdout, reports)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// report is the statistics of a file, or of the whole corpus.
+type report struct {
+	Name  string          `json:"name"`
+	Stats nlp.CorpusStats `json:"stats"`
+}
+
+// totalName is the name of the report for the whole corpus.
+const totalName = "total"
+
+// run returns the report for the files in paths, last, and before it a report for every file if each is set.
+func run(paths []string, ext string, top int, each bool) ([]report, error) {
+	files, err := listFiles(paths, ext)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no %s files", ext)
+	}
+
+	var reports []report
+	var total nlp.CorpusCounter
+	for _, path := range files {
+		text, err := nlp.ReadFileUTF8(path)
+		if err != nil {
+			return nil, err
+		}
+		total.Add(text)
+		if each {
+			var cc nlp.CorpusCounter
+			cc.Add(text)
+			reports = append(reports, report{path, cc.Stats(top)})
+		}
+	}
+	return append(reports, report{totalName, total.Stats(top)}), nil
+}
+
+// listFiles returns the files in paths, and in the directories in paths the files with the ext extension.
+func listFiles(paths []string, ext string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && filepath.Ext(p) == ext {
+				files = append(files, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+var writers = map[string]func(io.Writer, []report) error{
+	"text": writeText,
+	"json": writeJSON,
+	"csv":  writeCSV,
+}
+
+func writeText(w io.Writer, reports []report) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for i, r := range reports {
+		if i > 0 {
+			fmt.Fprintln(tw)
+		}
+		st := r.Stats
+		fmt.Fprintf(tw, "%s\n", r.Name)
+		fmt.Fprintf(tw, "  documents\t%d\n", st.Documents)
+		fmt.Fprintf(tw, "  sentences\t%d\n", st.Sentences)
+		fmt.Fprintf(tw, "  tokens\t%d\n", st.Tokens)
+		fmt.Fprintf(tw, "  types\t%d\n", st.Types)
+		fmt.Fprintf(tw, "  type-token ratio\t%.4f\n", st.TypeTokenRatio)
+		fmt.Fprintf(tw, "  hapax legomena\t%d\n", st.HapaxLegomena)
+		fmt.Fprintf(tw, "  avg word length\t%.2f letters\n", st.AvgWordLength)
+		fmt.Fprintf(tw, "  avg sentence length\t%.2f tokens\n", st.AvgSentenceLength)
+		fmt.Fprintf(tw, "  zipf\tslope %.3f, R² %.3f\n", st.Zipf.Slope, st.Zipf.R2)
+		fmt.Fprintf(tw, "  heaps\tK %.2f, β %.3f, R² %.3f\n", st.Heaps.K, st.Heaps.Beta, st.Heaps.R2)
+		if len(st.Top) > 0 {
+			words := make([]string, len(st.Top))
+			for i, wc := range st.Top {
+				words[i] = fmt.Sprintf("%s (%d)", wc.Word, wc.Count)
+			}
+			fmt.Fprintf(tw, "  top words\t%s\n", strings.Join(words, ", "))
+		}
+	}
+	return tw.Flush()
+}
+
+func writeJSON(w io.Writer, reports []report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(reports)
+}
+
+// writeCSV writes a row per report. The growth points and the top words don't fit in a row, use JSON for them.
+func writeCSV(w io.Writer, reports []report) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{
+		"name", "documents", "sentences", "tokens", "types", "type_token_ratio", "hapax_legomena",
+		"avg_word_length", "avg_sentence_length", "zipf_slope", "zipf_r2", "heaps_k", "heaps_beta", "heaps_r2",
+	})
+	for _, r := range reports {
+		st := r.Stats
+		cw.Write([]string{
+			r.Name, strconv.Itoa(st.Documents), strconv.Itoa(st.Sentences), strconv.Itoa(st.Tokens), strconv.Itoa(st.Types),
+			formatFloat(st.TypeTokenRatio), strconv.Itoa(st.HapaxLegomena), formatFloat(st.AvgWordLength), formatFloat(st.AvgSentenceLength),
+			formatFloat(st.Zipf.Slope), formatFloat(st.Zipf.R2), formatFloat(st.Heaps.K), formatFloat(st.Heaps.Beta), formatFloat(st.Heaps.R2),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', 4, 64)
+}
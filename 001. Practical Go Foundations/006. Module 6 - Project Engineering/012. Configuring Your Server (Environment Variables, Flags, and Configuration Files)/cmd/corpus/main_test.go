@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_run(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("The cat sat. The dog sat."), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sub", "b.txt"), []byte("A cat ran."), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.md"), []byte("Not read."), 0o644))
+
+	reports, err := run([]string{dir}, ".txt", 3, true)
+	require.NoError(t, err)
+	require.Len(t, reports, 3)
+	require.Equal(t, totalName, reports[2].Name)
+	require.Equal(t, 2, reports[2].Stats.Documents)
+	require.Equal(t, 9, reports[2].Stats.Tokens)
+	require.Equal(t, 3, reports[1].Stats.Tokens)
+
+	var buf bytes.Buffer
+	require.NoError(t, writeCSV(&buf, reports))
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 4)
+	require.Equal(t, []string{"total", "2", "3", "9"}, rows[3][:4])
+
+	for name, write := range writers {
+		buf.Reset()
+		require.NoError(t, write(&buf, reports), name)
+		require.Contains(t, buf.String(), "total", name)
+	}
+
+	_, err = run([]string{filepath.Join(dir, "sub")}, ".md", 3, false)
+	require.Error(t, err)
+}
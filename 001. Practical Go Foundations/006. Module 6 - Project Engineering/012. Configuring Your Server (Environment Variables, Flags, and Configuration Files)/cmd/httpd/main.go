@@ -2,13 +2,19 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"expvar" // Navigate to http://localhost:8080/debug/vars to view the output of the expvar package.
 	"flag"
 	"fmt"
 	"io"
 	"log/slog"
+	"mime"
 	"net/http"
 	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 
 	"nlp"
 	"nlp/stemmer"
@@ -42,7 +48,11 @@ Configuration.
 		- Doing these two steps first will save you a lot of trouble down the road. */
 
 var config struct {
-	Addr string
+	Addr      string
+	NamesFile string
+	BPE       string
+	Corpus    string
+	Vectors   string
 }
 
 func main() {
@@ -64,6 +74,10 @@ func main() {
 	So, for example, by running the following command in the terminal:
 	"NLP_ADDR=:9999 go run ./cmd/httpd -addr :8888" */
 	flag.StringVar(&config.Addr, "addr", config.Addr, "Address to listen on")
+	flag.StringVar(&config.NamesFile, "names", "", "File with names to redact, one per line")
+	flag.StringVar(&config.BPE, "bpe", "", "Prefix of the BPE vocabulary files (<prefix>.vocab and <prefix>.merges), see ./cmd/bpe")
+	flag.StringVar(&config.Corpus, "corpus", "", "Text file for /concordance and /complete")
+	flag.StringVar(&config.Vectors, "vectors", "", "Word vectors for /similar, in GloVe or word2vec format (binary if the file ends with .bin)")
 	flag.Parse()
 
 	// TODO: Validate configuration.
@@ -82,11 +96,58 @@ func main() {
 		log: slog.Default().With("app", "nlp"),
 	}
 
+	if config.NamesFile != "" {
+		names, err := loadNames(config.NamesFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading names - %s\n", err)
+			os.Exit(1)
+		}
+		api.names = names
+	}
+
+	if config.BPE != "" {
+		bpe, err := loadBPE(config.BPE)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading BPE vocabulary - %s\n", err)
+			os.Exit(1)
+		}
+		api.bpe = bpe
+	}
+
+	if config.Corpus != "" {
+		text, err := nlp.ReadFileUTF8(config.Corpus)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading corpus - %s\n", err)
+			os.Exit(1)
+		}
+		api.corpus = nlp.NewConcordance(text)
+		api.complete = nlp.NewAutocomplete(nlp.WordFrequencies(text))
+	}
+
+	if config.Vectors != "" {
+		vectors, err := loadEmbeddings(config.Vectors)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading word vectors - %s\n", err)
+			os.Exit(1)
+		}
+		api.vectors = vectors
+	}
+
 	// Routing.
 	// You can test the routes below using the REST Client tests in the ./requests.http file.
 	http.HandleFunc("GET /health", api.healthHandler)
 	http.HandleFunc("POST /tokenize", api.tokenizeHandler)
 	http.HandleFunc("GET /stem/{word}", api.stemHandler)
+	http.HandleFunc("GET /phonetic/{word}", api.phoneticHandler)
+	http.HandleFunc("POST /redact", api.redactHandler)
+	http.HandleFunc("POST /language", api.languageHandler)
+	http.HandleFunc("POST /readability", api.readabilityHandler)
+	http.HandleFunc("POST /encode", api.encodeHandler)
+	http.HandleFunc("POST /decode", api.decodeHandler)
+	http.HandleFunc("GET /concordance", api.concordanceHandler)
+	http.HandleFunc("GET /similar/{word}", api.similarHandler)
+	http.HandleFunc("GET /complete", api.completeHandler)
+	http.HandleFunc("POST /chunk", api.chunkHandler)
 
 	// Spin up the web server.
 	api.log.Info("server starting", "address", config.Addr) // Logging.
@@ -116,10 +177,23 @@ func (a *API) tokenizeHandler(w http.ResponseWriter, r *http.Request) {
 
 	// STEP 1:
 	// Read the data.
-	// TODO: Implement http.MaxBytesReader.
-	data, err := io.ReadAll(r.Body)
+	// The body is decoded to UTF-8, from the charset in the content-type header (e.g. "text/plain; charset=utf-16le") if there is one.
+	r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
+	body, err := bodyReader(r)
 	if err != nil {
 		a.log.Error("read", "error", err, "remote", r.RemoteAddr) // Logging.
+		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+		return // Always remember to return after http.Error.
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		a.log.Error("read", "error", err, "remote", r.RemoteAddr) // Logging.
+		var ierr *nlp.InvalidSequenceError
+		if errors.As(err, &ierr) {
+			http.Error(w, ierr.Error(), http.StatusBadRequest)
+			return // Always remember to return after http.Error.
+		}
 		http.Error(w, "Can't read the HTTP request body", http.StatusBadRequest)
 		return // Always remember to return after http.Error.
 	}
@@ -134,9 +208,26 @@ func (a *API) tokenizeHandler(w http.ResponseWriter, r *http.Request) {
 		return // Always remember to return after http.Error.
 	}
 
+	/* The optional "lang" query parameter selects the stop words and stemmer:
+	"?lang=de" for German, or "?lang=auto" to detect the language of the text. */
+	lang := r.URL.Query().Get("lang")
+	if lang != "" && lang != "auto" && !slices.Contains(nlp.Languages(), lang) {
+		a.log.Error("read", "error", "unknown language", "lang", lang) // Logging.
+		http.Error(w, "Unknown language", http.StatusBadRequest)
+		return // Always remember to return after http.Error.
+	}
+
 	// STEP 2:
 	// Do the work.
-	tokens := nlp.Tokenize(text)
+	var tokens []string
+	switch lang {
+	case "":
+		tokens = nlp.Tokenize(text)
+	case "auto":
+		lang, tokens = nlp.TokenizeAuto(text)
+	default:
+		tokens = nlp.AnalyzerFor(lang).Tokenize(text)
+	}
 
 	// STEP 3:
 	// Encode the response.
@@ -144,6 +235,9 @@ func (a *API) tokenizeHandler(w http.ResponseWriter, r *http.Request) {
 	resp := map[string]any{
 		"tokens": tokens,
 	}
+	if lang != "" {
+		resp["lang"] = lang
+	}
 	json.NewEncoder(w).Encode(resp)
 
 }
@@ -159,18 +253,555 @@ func (a *API) stemHandler(w http.ResponseWriter, r *http.Request) {
 	fmt.Fprintln(w, stemmer.Stem(word))
 }
 
+// phoneticHandler (GET dynamic route handler).
+// The optional "algo" query parameter selects one algorithm (e.g. "?algo=soundex"), by default all the codes are returned.
+func (a *API) phoneticHandler(w http.ResponseWriter, r *http.Request) {
+	// STEP 1:
+	// Read, parse, and validate the data.
+	word := r.PathValue("word")
+	algo := r.URL.Query().Get("algo")
+	if _, ok := nlp.PhoneticEncoders[algo]; algo != "" && !ok {
+		a.log.Error("phonetic", "error", "unknown algorithm", "algo", algo) // Logging.
+		http.Error(w, "Unknown algorithm", http.StatusBadRequest)
+		return // Always remember to return after http.Error.
+	}
+
+	// STEP 2:
+	// Do the work.
+	codes := make(map[string]string)
+	for name, encode := range nlp.PhoneticEncoders {
+		if algo == "" || algo == name {
+			codes[name] = encode(word)
+		}
+	}
+
+	// STEP 3:
+	// Encode the response.
+	w.Header().Set("content-type", "application/json")
+	resp := map[string]any{
+		"word":  word,
+		"codes": codes,
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+// redactHandler (POST route handler).
+func (a *API) redactHandler(w http.ResponseWriter, r *http.Request) {
+	// STEP 1:
+	// Read, parse, and validate the data.
+	var req struct {
+		Text   string     `json:"text"`
+		Policy nlp.Policy `json:"policy"`
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		a.log.Error("redact", "error", err, "remote", r.RemoteAddr) // Logging.
+		http.Error(w, "Can't parse the HTTP request body", http.StatusBadRequest)
+		return // Always remember to return after http.Error.
+	}
+
+	if len(req.Text) == 0 {
+		a.log.Error("redact", "error", "empty request") // Logging.
+		http.Error(w, "Empty request received", http.StatusBadRequest)
+		return // Always remember to return after http.Error.
+	}
+
+	if err := req.Policy.Validate(); err != nil {
+		a.log.Error("redact", "error", err) // Logging.
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return // Always remember to return after http.Error.
+	}
+
+	// STEP 2:
+	// Do the work.
+	// Never log the text itself, it's what we're trying to scrub.
+	req.Policy.Names = append(req.Policy.Names, a.names...)
+	text, redactions := nlp.Redact(req.Text, req.Policy)
+	redactCalls.Add(1) // Metrics.
+
+	// STEP 3:
+	// Encode the response.
+	w.Header().Set("content-type", "application/json")
+	resp := map[string]any{
+		"text":       text,
+		"redactions": redactions,
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+// languageHandler (POST route handler).
+func (a *API) languageHandler(w http.ResponseWriter, r *http.Request) {
+	// STEP 1:
+	// Read, parse, and validate the data.
+	body, err := bodyReader(r)
+	if err != nil {
+		a.log.Error("language", "error", err, "remote", r.RemoteAddr) // Logging.
+		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+		return // Always remember to return after http.Error.
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, io.NopCloser(body), maxBodySize))
+	if err != nil {
+		a.log.Error("language", "error", err, "remote", r.RemoteAddr) // Logging.
+		http.Error(w, "Can't read the HTTP request body", http.StatusBadRequest)
+		return // Always remember to return after http.Error.
+	}
+
+	if len(data) == 0 {
+		a.log.Error("language", "error", "empty request") // Logging.
+		http.Error(w, "Empty request received", http.StatusBadRequest)
+		return // Always remember to return after http.Error.
+	}
+
+	// STEP 2:
+	// Do the work.
+	guesses := nlp.DetectLanguage(string(data))
+	guesses = guesses[:min(len(guesses), maxGuesses)]
+	if guesses == nil {
+		guesses = []nlp.LanguageGuess{} // Encode as [] rather than null.
+	}
+
+	// STEP 3:
+	// Encode the response.
+	w.Header().Set("content-type", "application/json")
+	resp := map[string]any{
+		"languages": guesses,
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+// readabilityHandler (POST route handler).
+func (a *API) readabilityHandler(w http.ResponseWriter, r *http.Request) {
+	// STEP 1:
+	// Read, parse, and validate the data.
+	body, err := bodyReader(r)
+	if err != nil {
+		a.log.Error("readability", "error", err, "remote", r.RemoteAddr) // Logging.
+		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+		return // Always remember to return after http.Error.
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, io.NopCloser(body), maxBodySize))
+	if err != nil {
+		a.log.Error("readability", "error", err, "remote", r.RemoteAddr) // Logging.
+		http.Error(w, "Can't read the HTTP request body", http.StatusBadRequest)
+		return // Always remember to return after http.Error.
+	}
+
+	// STEP 2:
+	// Do the work.
+	scores, err := nlp.Readability(string(data))
+	if err != nil {
+		a.log.Error("readability", "error", err) // Logging.
+		http.Error(w, "No words in the request", http.StatusBadRequest)
+		return // Always remember to return after http.Error.
+	}
+
+	// STEP 3:
+	// Encode the response.
+	w.Header().Set("content-type", "application/json")
+	json.NewEncoder(w).Encode(scores)
+}
+
+// encodeHandler (POST route handler).
+func (a *API) encodeHandler(w http.ResponseWriter, r *http.Request) {
+	// STEP 1:
+	// Read, parse, and validate the data.
+	if a.bpe == nil {
+		a.log.Error("encode", "error", "no BPE vocabulary") // Logging.
+		http.Error(w, "No BPE vocabulary loaded", http.StatusServiceUnavailable)
+		return // Always remember to return after http.Error.
+	}
+
+	body, err := bodyReader(r)
+	if err != nil {
+		a.log.Error("encode", "error", err, "remote", r.RemoteAddr) // Logging.
+		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+		return // Always remember to return after http.Error.
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, io.NopCloser(body), maxBodySize))
+	if err != nil {
+		a.log.Error("encode", "error", err, "remote", r.RemoteAddr) // Logging.
+		http.Error(w, "Can't read the HTTP request body", http.StatusBadRequest)
+		return // Always remember to return after http.Error.
+	}
+
+	// STEP 2:
+	// Do the work.
+	ids := a.bpe.Encode(string(data))
+	if ids == nil {
+		ids = []int{} // Encode as [] rather than null.
+	}
+
+	// STEP 3:
+	// Encode the response.
+	w.Header().Set("content-type", "application/json")
+	resp := map[string]any{
+		"ids":   ids,
+		"count": len(ids),
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+// decodeHandler (POST route handler).
+func (a *API) decodeHandler(w http.ResponseWriter, r *http.Request) {
+	// STEP 1:
+	// Read, parse, and validate the data.
+	if a.bpe == nil {
+		a.log.Error("decode", "error", "no BPE vocabulary") // Logging.
+		http.Error(w, "No BPE vocabulary loaded", http.StatusServiceUnavailable)
+		return // Always remember to return after http.Error.
+	}
+
+	var req struct {
+		IDs []int `json:"ids"`
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		a.log.Error("decode", "error", err, "remote", r.RemoteAddr) // Logging.
+		http.Error(w, "Can't parse the HTTP request body", http.StatusBadRequest)
+		return // Always remember to return after http.Error.
+	}
+
+	// STEP 2:
+	// Do the work.
+	text, err := a.bpe.Decode(req.IDs)
+	if err != nil {
+		a.log.Error("decode", "error", err) // Logging.
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return // Always remember to return after http.Error.
+	}
+
+	// STEP 3:
+	// Encode the response.
+	w.Header().Set("content-type", "application/json")
+	resp := map[string]any{
+		"text": text,
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
 // Helper functions.
 func health() error {
 	// TODO: Implement the actual health check.
 	return nil
 }
 
+// bodyReader returns a reader that decodes the request body to UTF-8.
+// It uses the charset parameter of the content type if there is one, otherwise it detects the encoding.
+func bodyReader(r *http.Request) (*nlp.Reader, error) {
+	var dec *nlp.Reader
+	charset := ""
+	if ctype := r.Header.Get("Content-Type"); ctype != "" {
+		_, params, err := mime.ParseMediaType(ctype)
+		if err != nil {
+			return nil, err
+		}
+		charset = params["charset"]
+	}
+
+	if charset == "" {
+		dec = nlp.NewReader(r.Body)
+	} else {
+		enc, err := nlp.ParseCharset(charset)
+		if err != nil {
+			return nil, err
+		}
+		dec = nlp.NewReaderEncoding(r.Body, enc)
+	}
+	dec.Strict = true // Report invalid byte sequences instead of tokenizing garbage.
+	return dec, nil
+}
+
+// concordanceHandler (GET route handler with query parameters).
+// "q" is the word to look for, "width" the number of words of context (default 5) and "sort" the order ("text", "left" or "right").
+func (a *API) concordanceHandler(w http.ResponseWriter, r *http.Request) {
+	// STEP 1:
+	// Read, parse, and validate the data.
+	if a.corpus == nil {
+		a.log.Error("concordance", "error", "no corpus") // Logging.
+		http.Error(w, "No corpus loaded", http.StatusServiceUnavailable)
+		return // Always remember to return after http.Error.
+	}
+
+	query := r.URL.Query()
+	q := strings.TrimSpace(query.Get("q"))
+	if q == "" {
+		a.log.Error("concordance", "error", "missing query") // Logging.
+		http.Error(w, "Missing q parameter", http.StatusBadRequest)
+		return // Always remember to return after http.Error.
+	}
+
+	width := defaultKWICWidth
+	if s := query.Get("width"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 || n > maxKWICWidth {
+			a.log.Error("concordance", "error", "bad width", "width", s) // Logging.
+			http.Error(w, fmt.Sprintf("width must be between 0 and %d", maxKWICWidth), http.StatusBadRequest)
+			return // Always remember to return after http.Error.
+		}
+		width = n
+	}
+
+	order, err := nlp.ParseKWICOrder(query.Get("sort"))
+	if err != nil {
+		a.log.Error("concordance", "error", err) // Logging.
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return // Always remember to return after http.Error.
+	}
+
+	// STEP 2:
+	// Do the work.
+	lines := a.corpus.Find(q, width)
+	nlp.SortKWIC(lines, order)
+	if lines == nil {
+		lines = []nlp.KWICLine{} // Encode as [], not null.
+	}
+
+	// STEP 3:
+	// Encode the response.
+	w.Header().Set("content-type", "application/json")
+	resp := map[string]any{
+		"query": q,
+		"count": len(lines),
+		"lines": lines,
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+// similarHandler (GET dynamic route handler).
+// The optional "n" query parameter is the number of words returned (default 10).
+func (a *API) similarHandler(w http.ResponseWriter, r *http.Request) {
+	// STEP 1:
+	// Read, parse, and validate the data.
+	if a.vectors == nil {
+		a.log.Error("similar", "error", "no word vectors") // Logging.
+		http.Error(w, "No word vectors loaded", http.StatusServiceUnavailable)
+		return // Always remember to return after http.Error.
+	}
+
+	word := strings.ToLower(r.PathValue("word"))
+	n := defaultSimilar
+	if s := r.URL.Query().Get("n"); s != "" {
+		var err error
+		n, err = strconv.Atoi(s)
+		if err != nil || n < 1 || n > maxSimilar {
+			a.log.Error("similar", "error", "bad n", "n", s) // Logging.
+			http.Error(w, fmt.Sprintf("n must be between 1 and %d", maxSimilar), http.StatusBadRequest)
+			return // Always remember to return after http.Error.
+		}
+	}
+
+	// STEP 2:
+	// Do the work.
+	similar, ok := a.vectors.Nearest(word, n)
+	if !ok {
+		a.log.Error("similar", "error", "unknown word", "word", word) // Logging.
+		http.Error(w, "Unknown word", http.StatusNotFound)
+		return // Always remember to return after http.Error.
+	}
+
+	// STEP 3:
+	// Encode the response.
+	w.Header().Set("content-type", "application/json")
+	resp := map[string]any{
+		"word":    word,
+		"similar": similar,
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+// completeHandler (GET route handler with query parameters).
+// "prefix" is the start of the word, "n" the number of completions (default 10) and "typo=true" allows one typo in the prefix.
+func (a *API) completeHandler(w http.ResponseWriter, r *http.Request) {
+	// STEP 1:
+	// Read, parse, and validate the data.
+	if a.complete == nil {
+		a.log.Error("complete", "error", "no corpus") // Logging.
+		http.Error(w, "No corpus loaded", http.StatusServiceUnavailable)
+		return // Always remember to return after http.Error.
+	}
+
+	query := r.URL.Query()
+	prefix := strings.ToLower(strings.TrimSpace(query.Get("prefix")))
+	if prefix == "" {
+		a.log.Error("complete", "error", "missing prefix") // Logging.
+		http.Error(w, "Missing prefix parameter", http.StatusBadRequest)
+		return // Always remember to return after http.Error.
+	}
+
+	n := defaultCompletions
+	if s := query.Get("n"); s != "" {
+		var err error
+		n, err = strconv.Atoi(s)
+		if err != nil || n < 1 || n > maxCompletions {
+			a.log.Error("complete", "error", "bad n", "n", s) // Logging.
+			http.Error(w, fmt.Sprintf("n must be between 1 and %d", maxCompletions), http.StatusBadRequest)
+			return // Always remember to return after http.Error.
+		}
+	}
+
+	typo := false
+	if s := query.Get("typo"); s != "" {
+		var err error
+		typo, err = strconv.ParseBool(s)
+		if err != nil {
+			a.log.Error("complete", "error", "bad typo", "typo", s) // Logging.
+			http.Error(w, "typo must be true or false", http.StatusBadRequest)
+			return // Always remember to return after http.Error.
+		}
+	}
+
+	// STEP 2:
+	// Do the work.
+	var completions []nlp.Completion
+	if typo {
+		completions = a.complete.CompleteFuzzy(prefix, n)
+	} else {
+		completions = a.complete.Complete(prefix, n)
+	}
+	if completions == nil {
+		completions = []nlp.Completion{} // Encode as [], not null.
+	}
+
+	// STEP 3:
+	// Encode the response.
+	w.Header().Set("content-type", "application/json")
+	resp := map[string]any{
+		"prefix":      prefix,
+		"completions": completions,
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+// chunkHandler (POST route handler).
+// The body is {"text": ..., "max_tokens": 200, "overlap": 20, "counter": "words"}, the counter is "words" (the default) or "bpe".
+func (a *API) chunkHandler(w http.ResponseWriter, r *http.Request) {
+	// STEP 1:
+	// Read, parse, and validate the data.
+	var req struct {
+		Text      string `json:"text"`
+		MaxTokens int    `json:"max_tokens"`
+		Overlap   int    `json:"overlap"`
+		Counter   string `json:"counter"`
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		a.log.Error("chunk", "error", err, "remote", r.RemoteAddr) // Logging.
+		http.Error(w, "Can't parse the HTTP request body", http.StatusBadRequest)
+		return // Always remember to return after http.Error.
+	}
+
+	opts := nlp.ChunkOptions{MaxTokens: req.MaxTokens, Overlap: req.Overlap}
+	switch req.Counter {
+	case "", "words":
+		// Default counter.
+	case "bpe":
+		if a.bpe == nil {
+			a.log.Error("chunk", "error", "no BPE vocabulary") // Logging.
+			http.Error(w, "No BPE vocabulary loaded", http.StatusServiceUnavailable)
+			return // Always remember to return after http.Error.
+		}
+		opts.Count = a.bpe.Count
+	default:
+		a.log.Error("chunk", "error", "bad counter", "counter", req.Counter) // Logging.
+		http.Error(w, `counter must be "words" or "bpe"`, http.StatusBadRequest)
+		return // Always remember to return after http.Error.
+	}
+
+	// STEP 2:
+	// Do the work.
+	chunks, err := nlp.ChunkText(req.Text, opts)
+	if err != nil {
+		a.log.Error("chunk", "error", err) // Logging.
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return // Always remember to return after http.Error.
+	}
+	if chunks == nil {
+		chunks = []nlp.Chunk{} // Encode as [], not null.
+	}
+
+	// STEP 3:
+	// Encode the response.
+	w.Header().Set("content-type", "application/json")
+	resp := map[string]any{
+		"chunks": chunks,
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+// loadNames loads the redaction gazetteer from a file.
+func loadNames(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return nlp.LoadGazetteer(file)
+}
+
+// loadBPE loads the BPE vocabulary from <prefix>.vocab and <prefix>.merges.
+func loadBPE(prefix string) (*nlp.BPE, error) {
+	vocab, err := os.Open(prefix + ".vocab")
+	if err != nil {
+		return nil, err
+	}
+	defer vocab.Close()
+
+	merges, err := os.Open(prefix + ".merges")
+	if err != nil {
+		return nil, err
+	}
+	defer merges.Close()
+
+	return nlp.LoadBPE(vocab, merges)
+}
+
+// loadEmbeddings loads the word vectors for /similar, in word2vec binary format if path ends with ".bin", or in text format.
+func loadEmbeddings(path string) (*nlp.Embeddings, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	format := nlp.TextEmbeddings
+	if filepath.Ext(path) == ".bin" {
+		format = nlp.BinaryEmbeddings
+	}
+	return nlp.LoadEmbeddings(file, format)
+}
+
+const (
+	// Maximum size of a JSON request body.
+	maxBodySize = 1 << 20 // 1MB
+	// Maximum number of languages returned by /language.
+	maxGuesses = 5
+	// Default and maximum number of words of context returned by /concordance.
+	defaultKWICWidth = 5
+	maxKWICWidth     = 50
+	// Default and maximum number of words returned by /similar.
+	defaultSimilar = 10
+	maxSimilar     = 100
+	// Default and maximum number of completions returned by /complete.
+	defaultCompletions = 10
+	maxCompletions     = 50
+)
+
 // Logging.
 type API struct {
-	log *slog.Logger
+	log      *slog.Logger
+	names    []string          // Gazetteer for redaction.
+	bpe      *nlp.BPE          // Subword tokenizer for /encode and /decode, nil if not configured.
+	corpus   *nlp.Concordance  // Text searched by /concordance, nil if not configured.
+	vectors  *nlp.Embeddings   // Word vectors for /similar, nil if not configured.
+	complete *nlp.Autocomplete // Words of the corpus for /complete, nil if not configured.
 }
 
 // Metrics.
 var (
-	stemCalls = expvar.NewInt("stem.calls")
+	stemCalls   = expvar.NewInt("stem.calls")
+	redactCalls = expvar.NewInt("redact.calls")
 )
@@ -1,11 +1,15 @@
 package main
 
 import (
+	"encoding/json"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"nlp"
+
 	"github.com/stretchr/testify/require"
 )
 
@@ -24,3 +28,376 @@ func Test_healthHandler(t *testing.T) {
 	// Using testify.
 	require.Equal(t, http.StatusOK, resp.StatusCode)
 }
+
+func Test_redactHandler(t *testing.T) {
+	body := `{"text": "Mail holmes@baker.st or Irene Adler", "policy": {"actions": {"email": "mask"}}}`
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/redact", strings.NewReader(body))
+
+	api := API{log: slog.Default(), names: []string{"Irene Adler"}}
+	api.redactHandler(w, r)
+
+	resp := w.Result()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var reply struct {
+		Text string
+	}
+	err := json.NewDecoder(resp.Body).Decode(&reply)
+	require.NoError(t, err)
+	require.Equal(t, "Mail ******@*****.** or [NAME]", reply.Text)
+}
+
+func Test_redactHandlerBadPolicy(t *testing.T) {
+	body := `{"text": "Mail holmes@baker.st", "policy": {"default": "shred"}}`
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/redact", strings.NewReader(body))
+
+	api := API{log: slog.Default()}
+	api.redactHandler(w, r)
+
+	require.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+}
+
+func Test_tokenizeHandlerCharset(t *testing.T) {
+	var cases = []struct {
+		name   string
+		ctype  string
+		body   string
+		status int
+		tokens []string
+	}{
+		{"utf-8 bom", "text/plain", "\xef\xbb\xbfCaf\xc3\xa9 on first", http.StatusOK, []string{"caf", "on", "first"}},
+		{"latin-1", "text/plain; charset=ISO-8859-1", "Who's on first\xa0now", http.StatusOK, []string{"who", "on", "first", "now"}},
+		{"utf-16le", "text/plain; charset=utf-16le", "W\x00h\x00o\x00", http.StatusOK, []string{"who"}},
+		{"invalid", "text/plain; charset=utf-8", "Who\xff", http.StatusBadRequest, nil},
+		{"unsupported", "text/plain; charset=ebcdic", "Who", http.StatusUnsupportedMediaType, nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/tokenize", strings.NewReader(tc.body))
+			r.Header.Set("Content-Type", tc.ctype)
+
+			api := API{log: slog.Default()}
+			api.tokenizeHandler(w, r)
+
+			resp := w.Result()
+			require.Equal(t, tc.status, resp.StatusCode)
+			if tc.status != http.StatusOK {
+				return
+			}
+
+			var reply struct {
+				Tokens []string
+			}
+			err := json.NewDecoder(resp.Body).Decode(&reply)
+			require.NoError(t, err)
+			require.Equal(t, tc.tokens, reply.Tokens)
+		})
+	}
+}
+
+func Test_tokenizeHandlerLang(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/tokenize?lang=auto", strings.NewReader("Die Kinder spielten mit den Zeitungen."))
+
+	api := API{log: slog.Default()}
+	api.tokenizeHandler(w, r)
+
+	resp := w.Result()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var reply struct {
+		Lang   string
+		Tokens []string
+	}
+	err := json.NewDecoder(resp.Body).Decode(&reply)
+	require.NoError(t, err)
+	require.Equal(t, "de", reply.Lang)
+	require.Equal(t, []string{"kind", "spielt", "zeit"}, reply.Tokens)
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodPost, "/tokenize?lang=xx", strings.NewReader("Who's on first?"))
+	api.tokenizeHandler(w, r)
+	require.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+}
+
+func Test_tokenizeHandlerTooLarge(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/tokenize", strings.NewReader(strings.Repeat("a ", maxBodySize)))
+
+	api := API{log: slog.Default()}
+	api.tokenizeHandler(w, r)
+	require.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+}
+
+func Test_languageHandler(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/language", strings.NewReader("Le vieil homme marchait lentement le long de la rivière."))
+
+	api := API{log: slog.Default()}
+	api.languageHandler(w, r)
+
+	resp := w.Result()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var reply struct {
+		Languages []struct {
+			Lang       string
+			Confidence float64
+		}
+	}
+	err := json.NewDecoder(resp.Body).Decode(&reply)
+	require.NoError(t, err)
+	require.NotEmpty(t, reply.Languages)
+	require.LessOrEqual(t, len(reply.Languages), maxGuesses)
+	require.Equal(t, "fr", reply.Languages[0].Lang)
+}
+
+func Test_encodeDecodeHandlers(t *testing.T) {
+	bpe, err := nlp.TrainBPE(strings.NewReader("the cat and the hat and the bat"), 300)
+	require.NoError(t, err)
+	api := API{log: slog.Default(), bpe: bpe}
+
+	text := "the bath"
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/encode", strings.NewReader(text))
+	api.encodeHandler(w, r)
+
+	resp := w.Result()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var encoded struct {
+		IDs   []int
+		Count int
+	}
+	err = json.NewDecoder(resp.Body).Decode(&encoded)
+	require.NoError(t, err)
+	require.Equal(t, bpe.Encode(text), encoded.IDs)
+	require.Equal(t, len(encoded.IDs), encoded.Count)
+
+	body, err := json.Marshal(map[string]any{"ids": encoded.IDs})
+	require.NoError(t, err)
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodPost, "/decode", strings.NewReader(string(body)))
+	api.decodeHandler(w, r)
+
+	resp = w.Result()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var decoded struct {
+		Text string
+	}
+	err = json.NewDecoder(resp.Body).Decode(&decoded)
+	require.NoError(t, err)
+	require.Equal(t, text, decoded.Text)
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodPost, "/decode", strings.NewReader(`{"ids": [100000]}`))
+	api.decodeHandler(w, r)
+	require.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodPost, "/encode", strings.NewReader(text))
+	(&API{log: slog.Default()}).encodeHandler(w, r)
+	require.Equal(t, http.StatusServiceUnavailable, w.Result().StatusCode)
+}
+
+func Test_phoneticHandler(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/phonetic/Schmidt?algo=double_metaphone", nil)
+	r.SetPathValue("word", "Schmidt")
+
+	api := API{log: slog.Default()}
+	api.phoneticHandler(w, r)
+
+	resp := w.Result()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var reply struct {
+		Codes map[string]string
+	}
+	err := json.NewDecoder(resp.Body).Decode(&reply)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"double_metaphone": "XMT"}, reply.Codes)
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodGet, "/phonetic/Schmidt?algo=caverphone", nil)
+	r.SetPathValue("word", "Schmidt")
+	api.phoneticHandler(w, r)
+	require.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+}
+
+func Test_readabilityHandler(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/readability", strings.NewReader("The cat sat on the mat. It was fun."))
+
+	api := API{log: slog.Default()}
+	api.readabilityHandler(w, r)
+
+	resp := w.Result()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var reply nlp.ReadabilityScores
+	err := json.NewDecoder(resp.Body).Decode(&reply)
+	require.NoError(t, err)
+	require.Equal(t, 2, reply.Stats.Sentences)
+	require.Equal(t, 9, reply.Stats.Words)
+	require.Greater(t, reply.FleschReadingEase, 100.0)
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodPost, "/readability", strings.NewReader("?!"))
+	api.readabilityHandler(w, r)
+	require.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+}
+
+func Test_concordanceHandler(t *testing.T) {
+	api := API{
+		log:    slog.Default(),
+		corpus: nlp.NewConcordance("Holmes detects. I write about Holmes, who detected the crime."),
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/concordance?q=detect&width=1&sort=right", nil)
+	api.concordanceHandler(w, r)
+
+	resp := w.Result()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var reply struct {
+		Count int
+		Lines []nlp.KWICLine
+	}
+	err := json.NewDecoder(resp.Body).Decode(&reply)
+	require.NoError(t, err)
+	require.Equal(t, 2, reply.Count)
+	require.Equal(t, ". I", reply.Lines[0].Right) // "i" < "the".
+	require.Equal(t, "detected", reply.Lines[1].Keyword)
+
+	for _, target := range []string{"/concordance", "/concordance?q=x&width=-1", "/concordance?q=x&sort=up"} {
+		w = httptest.NewRecorder()
+		api.concordanceHandler(w, httptest.NewRequest(http.MethodGet, target, nil))
+		require.Equal(t, http.StatusBadRequest, w.Result().StatusCode, target)
+	}
+
+	w = httptest.NewRecorder()
+	api = API{log: slog.Default()}
+	api.concordanceHandler(w, httptest.NewRequest(http.MethodGet, "/concordance?q=x", nil))
+	require.Equal(t, http.StatusServiceUnavailable, w.Result().StatusCode)
+}
+
+func Test_similarHandler(t *testing.T) {
+	vectors, err := loadEmbeddings("../../testdata/vectors.txt")
+	require.NoError(t, err)
+	api := API{log: slog.Default(), vectors: vectors}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/similar/Cat?n=1", nil)
+	r.SetPathValue("word", "Cat")
+	api.similarHandler(w, r)
+
+	resp := w.Result()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var reply struct {
+		Word    string
+		Similar []nlp.Neighbor
+	}
+	err = json.NewDecoder(resp.Body).Decode(&reply)
+	require.NoError(t, err)
+	require.Equal(t, "cat", reply.Word)
+	require.Len(t, reply.Similar, 1)
+	require.Equal(t, "dog", reply.Similar[0].Word)
+
+	var cases = []struct {
+		word   string
+		target string
+		status int
+	}{
+		{"unicorn", "/similar/unicorn", http.StatusNotFound},
+		{"cat", "/similar/cat?n=0", http.StatusBadRequest},
+		{"cat", "/similar/cat?n=many", http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		w = httptest.NewRecorder()
+		r = httptest.NewRequest(http.MethodGet, tc.target, nil)
+		r.SetPathValue("word", tc.word)
+		api.similarHandler(w, r)
+		require.Equal(t, tc.status, w.Result().StatusCode, tc.target)
+	}
+}
+
+func Test_completeHandler(t *testing.T) {
+	api := API{
+		log:      slog.Default(),
+		complete: nlp.NewAutocomplete(nlp.WordFrequencies("Holmes held the hat. Holmes smiled. Watson was at home.")),
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/complete?prefix=Ho&n=2", nil)
+	api.completeHandler(w, r)
+
+	resp := w.Result()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var reply struct {
+		Prefix      string
+		Completions []nlp.Completion
+	}
+	err := json.NewDecoder(resp.Body).Decode(&reply)
+	require.NoError(t, err)
+	require.Equal(t, "ho", reply.Prefix)
+	require.Equal(t, []nlp.Completion{{Word: "holmes", Count: 2}, {Word: "home", Count: 1}}, reply.Completions)
+
+	w = httptest.NewRecorder()
+	api.completeHandler(w, httptest.NewRequest(http.MethodGet, "/complete?prefix=wta&typo=true", nil))
+	require.Equal(t, http.StatusOK, w.Result().StatusCode)
+	require.Contains(t, w.Body.String(), `"watson"`)
+
+	for _, target := range []string{"/complete", "/complete?prefix=ho&n=0", "/complete?prefix=ho&typo=maybe"} {
+		w = httptest.NewRecorder()
+		api.completeHandler(w, httptest.NewRequest(http.MethodGet, target, nil))
+		require.Equal(t, http.StatusBadRequest, w.Result().StatusCode, target)
+	}
+}
+
+func Test_chunkHandler(t *testing.T) {
+	api := API{log: slog.Default()}
+	text := "Sherlock Holmes took his bottle. He adjusted the needle.\n\nWatson watched him."
+	body, err := json.Marshal(map[string]any{"text": text, "max_tokens": 6, "overlap": 2})
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/chunk", strings.NewReader(string(body)))
+	api.chunkHandler(w, r)
+
+	resp := w.Result()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var reply struct {
+		Chunks []nlp.Chunk
+	}
+	err = json.NewDecoder(resp.Body).Decode(&reply)
+	require.NoError(t, err)
+	require.NotEmpty(t, reply.Chunks)
+	for _, c := range reply.Chunks {
+		require.Equal(t, text[c.Start:c.End], c.Text)
+		require.LessOrEqual(t, c.Tokens, 6)
+	}
+
+	for _, body := range []string{
+		`{"text": "a b c", "max_tokens": 0}`,
+		`{"text": "a b c", "max_tokens": 5, "overlap": 5}`,
+		`{"text": "a b c", "max_tokens": 5, "counter": "chars"}`,
+		`not json`,
+	} {
+		w = httptest.NewRecorder()
+		r = httptest.NewRequest(http.MethodPost, "/chunk", strings.NewReader(body))
+		api.chunkHandler(w, r)
+		require.Equal(t, http.StatusBadRequest, w.Result().StatusCode, body)
+	}
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodPost, "/chunk", strings.NewReader(`{"text": "a b c", "max_tokens": 5, "counter": "bpe"}`))
+	api.chunkHandler(w, r)
+	require.Equal(t, http.StatusServiceUnavailable, w.Result().StatusCode)
+}
@@ -0,0 +1,122 @@
+/*
+keyness prints the words that are overused and underused in a corpus (A), compared to a reference corpus (B).
+
+	go run ./cmd/keyness -top 20 a1.txt a2.txt -- b1.txt b2.txt
+
+Words are ranked by G² (log-likelihood), only the words with a p-value below -p are shown.
+*/
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"text/tabwriter"
+
+	"nlp"
+)
+
+func main() {
+	top := flag.Int("top", 20, "Number of words to show in each list")
+	p := flag.Float64("p", 0.05, "Maximal p-value of the words to show")
+	minCount := flag.Int("min", 5, "Minimal count of a word in both corpora together")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] A-FILE... -- B-FILE...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	filesA, filesB, err := splitArgs(flag.Args())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(os.Stdout, filesA, filesB, *top, *p, *minCount); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// splitArgs splits the arguments at "--".
+func splitArgs(args []string) (a, b []string, err error) {
+	i := slices.Index(args, "--")
+	if i <= 0 || i == len(args)-1 {
+		return nil, nil, errors.New("expected files for both corpora, separated by --")
+	}
+	return args[:i], args[i+1:], nil
+}
+
+func run(w io.Writer, filesA, filesB []string, top int, p float64, minCount int) error {
+	a, err := loadTable(filesA)
+	if err != nil {
+		return err
+	}
+	b, err := loadTable(filesB)
+	if err != nil {
+		return err
+	}
+
+	var over, under []nlp.Keyword
+	for _, kw := range nlp.Keyness(a, b, minCount) {
+		switch {
+		case kw.P >= p:
+		case kw.LogRatio > 0 && len(over) < top:
+			over = append(over, kw)
+		case kw.LogRatio < 0 && len(under) < top:
+			under = append(under, kw)
+		}
+	}
+
+	fmt.Fprintf(w, "A: %d tokens, B: %d tokens\n\n", a.Total(), b.Total())
+	if err := writeKeywords(w, "Overused in A", over); err != nil {
+		return err
+	}
+	fmt.Fprintln(w)
+	return writeKeywords(w, "Underused in A", under)
+}
+
+// loadTable counts the tokens of files.
+func loadTable(files []string) (nlp.FrequencyTable, error) {
+	ft := nlp.FrequencyTable{}
+	for _, path := range files {
+		text, err := nlp.ReadFileUTF8(path)
+		if err != nil {
+			return nil, err
+		}
+		ft.Add(text)
+	}
+	return ft, nil
+}
+
+func writeKeywords(w io.Writer, title string, keywords []nlp.Keyword) error {
+	fmt.Fprintln(w, title)
+	if len(keywords) == 0 {
+		fmt.Fprintln(w, "  (none)")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "word\tA\tB\tG²\tχ²\tlog ratio\tp\t")
+	for _, kw := range keywords {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.2f\t%.2f\t%.2f\t%s\t\n", kw.Word, kw.CountA, kw.CountB, kw.G2, kw.ChiSquare, kw.LogRatio, significance(kw.P))
+	}
+	return tw.Flush()
+}
+
+// significance formats a p-value the way papers do.
+func significance(p float64) string {
+	switch {
+	case p < 0.001:
+		return "<0.001"
+	case p < 0.01:
+		return "<0.01"
+	case p < 0.05:
+		return "<0.05"
+	}
+	return fmt.Sprintf("%.2f", p)
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_splitArgs(t *testing.T) {
+	a, b, err := splitArgs([]string{"a1", "a2", "--", "b1"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a1", "a2"}, a)
+	require.Equal(t, []string{"b1"}, b)
+
+	for _, args := range [][]string{{"a1", "b1"}, {"--", "b1"}, {"a1", "--"}} {
+		_, _, err := splitArgs(args)
+		require.Error(t, err, args)
+	}
+}
+
+func Test_run(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.txt")
+	b := filepath.Join(dir, "b.txt")
+	require.NoError(t, os.WriteFile(a, []byte(strings.Repeat("The ship sailed. ", 20)), 0o644))
+	require.NoError(t, os.WriteFile(b, []byte(strings.Repeat("The train left. ", 20)), 0o644))
+
+	var buf bytes.Buffer
+	err := run(&buf, []string{a}, []string{b}, 10, 0.05, 5)
+	require.NoError(t, err)
+	over, under, ok := strings.Cut(buf.String(), "Underused in A")
+	require.True(t, ok)
+	require.Contains(t, over, "ship")
+	require.NotContains(t, over, "the ")
+	require.Contains(t, under, "train")
+
+	err = run(&buf, []string{a}, []string{filepath.Join(dir, "missing.txt")}, 10, 0.05, 5)
+	require.Error(t, err)
+}
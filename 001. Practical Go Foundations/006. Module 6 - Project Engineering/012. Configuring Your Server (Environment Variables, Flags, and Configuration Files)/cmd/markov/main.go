@@ -0,0 +1,129 @@
+/*
+markov generates text with a Markov chain trained on a corpus, e.g. to load test cmd/httpd with realistic text.
+
+	go run ./cmd/markov -n 5 -prompt "Sherlock Holmes" testdata/sherlock.txt
+
+Train once and save the model with -save, then generate from it with -load:
+
+	go run ./cmd/markov -order 3 -save sherlock.markov -n 0 testdata/sherlock.txt
+	go run ./cmd/markov -load sherlock.markov -seed 7 -temp 0.8 -n 10
+
+The same seed generates the same text.
+*/
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+
+	"nlp"
+)
+
+type options struct {
+	order int
+	load  string
+	save  string
+	seed  uint64
+	gen   nlp.GenerateOptions
+}
+
+func main() {
+	var opts options
+	flag.IntVar(&opts.order, "order", 2, "Number of tokens in a state")
+	flag.StringVar(&opts.load, "load", "", "Load the model from a file, instead of training it")
+	flag.StringVar(&opts.save, "save", "", "Save the model to a file")
+	flag.Uint64Var(&opts.seed, "seed", 1, "Seed of the random number generator")
+	flag.StringVar(&opts.gen.Prompt, "prompt", "", "Start of the text")
+	flag.IntVar(&opts.gen.Sentences, "n", 1, "Number of sentences to generate, 0 to only train")
+	flag.IntVar(&opts.gen.MaxTokens, "max", 1000, "Maximal number of tokens")
+	flag.Float64Var(&opts.gen.Temperature, "temp", 1, "Temperature, lower is more predictable")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] FILE...\n       %s -load MODEL [flags]\n", os.Args[0], os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if (opts.load == "") == (flag.NArg() == 0) {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(os.Stdout, flag.Args(), opts); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(w io.Writer, paths []string, opts options) error {
+	var m *nlp.Markov
+	var err error
+	if opts.load != "" {
+		m, err = loadModel(opts.load)
+	} else {
+		m, err = train(paths, opts.order)
+	}
+	if err != nil {
+		return err
+	}
+
+	if opts.save != "" {
+		if err := saveModel(m, opts.save); err != nil {
+			return err
+		}
+	}
+
+	if opts.gen.Sentences == 0 {
+		return nil
+	}
+	text, err := m.Generate(rand.New(rand.NewPCG(opts.seed, opts.seed)), opts.gen)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, text)
+	return err
+}
+
+func train(paths []string, order int) (*nlp.Markov, error) {
+	m, err := nlp.NewMarkov(order)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		text, err := nlp.ReadFileUTF8(path)
+		if err != nil {
+			return nil, err
+		}
+		m.Add(text)
+	}
+	return m, nil
+}
+
+func loadModel(path string) (*nlp.Markov, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	m, err := nlp.LoadMarkov(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return m, nil
+}
+
+func saveModel(m *nlp.Markov, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := m.Save(file); err != nil {
+		return err
+	}
+	return file.Close()
+}
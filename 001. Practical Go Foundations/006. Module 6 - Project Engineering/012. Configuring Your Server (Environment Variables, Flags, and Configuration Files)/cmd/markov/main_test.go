@@ -0,0 +1,34 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"nlp"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_run(t *testing.T) {
+	dir := t.TempDir()
+	corpus := filepath.Join(dir, "corpus.txt")
+	model := filepath.Join(dir, "corpus.markov")
+	require.NoError(t, os.WriteFile(corpus, []byte("The cat sat on the mat. The dog sat on the cat."), 0o644))
+
+	// Train and save.
+	opts := options{order: 2, save: model, seed: 3, gen: nlp.GenerateOptions{Prompt: "The", Sentences: 2}}
+	var trained bytes.Buffer
+	require.NoError(t, run(&trained, []string{corpus}, opts))
+	require.Len(t, nlp.SplitSentences(trained.String()), 2)
+
+	// Load and generate the same text.
+	opts.load, opts.save = model, ""
+	var loaded bytes.Buffer
+	require.NoError(t, run(&loaded, nil, opts))
+	require.Equal(t, trained.String(), loaded.String())
+
+	opts.gen.Prompt = "A bird"
+	require.Error(t, run(&loaded, nil, opts))
+}
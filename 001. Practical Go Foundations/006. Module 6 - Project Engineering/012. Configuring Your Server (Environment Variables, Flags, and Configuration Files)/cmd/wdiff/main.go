@@ -0,0 +1,61 @@
+/*
+wdiff compares two versions of a text word by word.
+
+	go run ./cmd/wdiff -format ansi draft.txt final.txt
+
+Use "-format html" to get <del> and <ins> elements, and "-i" to ignore case.
+*/
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"nlp"
+)
+
+func main() {
+	format := flag.String("format", "text", "Output format: text, ansi or html")
+	ignoreCase := flag.Bool("i", false, "Ignore case")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] OLD NEW\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	diffFormat, err := nlp.ParseDiffFormat(*format)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		os.Exit(2)
+	}
+
+	if err := run(os.Stdout, flag.Arg(0), flag.Arg(1), diffFormat, *ignoreCase); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(w io.Writer, oldPath, newPath string, format nlp.DiffFormat, ignoreCase bool) error {
+	a, err := nlp.ReadFileUTF8(oldPath)
+	if err != nil {
+		return err
+	}
+	b, err := nlp.ReadFileUTF8(newPath)
+	if err != nil {
+		return err
+	}
+
+	var opts nlp.DiffOptions
+	if ignoreCase {
+		opts.Normalize = strings.ToLower
+	}
+	return nlp.WriteDiff(w, nlp.Diff(a, b, opts), format)
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"nlp"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_run(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.txt")
+	b := filepath.Join(dir, "b.txt")
+	require.NoError(t, os.WriteFile(a, []byte("Holmes took his bottle from the corner.\n"), 0o644))
+	require.NoError(t, os.WriteFile(b, []byte("\xef\xbb\xbfHOLMES took the bottle from the corner.\n"), 0o644)) // With a BOM.
+
+	var buf bytes.Buffer
+	err := run(&buf, a, b, nlp.DiffText, false)
+	require.NoError(t, err)
+	require.Equal(t, "[-Holmes-]{+HOLMES+} took [-his-]{+the+} bottle from the corner.\n", buf.String())
+
+	buf.Reset()
+	err = run(&buf, a, b, nlp.DiffHTML, true)
+	require.NoError(t, err)
+	require.Equal(t, "Holmes took <del>his</del><ins>the</ins> bottle from the corner.\n", buf.String())
+
+	err = run(&buf, a, filepath.Join(dir, "missing.txt"), nlp.DiffText, false)
+	require.Error(t, err)
+}
@@ -0,0 +1,215 @@
+package nlp
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+/*
+Collocations.
+Word counts show that "baker" and "street" are common words, but not that they go together.
+A collocation is a sequence of words that occurs more often than chance, e.g. "baker street" or "high school".
+CollocationFinder counts the bigrams (pairs of words) and trigrams of a corpus and scores them with a measure of association:
+	- PMI (pointwise mutual information): how much more often the words occur together than if they were independent.
+	  It favors rare words, so use it with a minimal count.
+	- T-score: how confident we are that the words are associated, it favors frequent n-grams.
+	- Log-likelihood (Dunning's G²): a good compromise, that works well for both rare and frequent words.
+*/
+
+// Measure is a measure of association between words.
+type Measure int
+
+const (
+	LogLikelihood Measure = iota
+	PMI
+	TScore
+)
+
+var measureNames = map[Measure]string{
+	LogLikelihood: "log_likelihood",
+	PMI:           "pmi",
+	TScore:        "t_score",
+}
+
+// String implements fmt.Stringer.
+func (m Measure) String() string {
+	if name, ok := measureNames[m]; ok {
+		return name
+	}
+	return fmt.Sprintf("Measure(%d)", int(m))
+}
+
+// ParseMeasure returns the Measure for name: "log_likelihood", "pmi" or "t_score".
+func ParseMeasure(name string) (Measure, error) {
+	for m, n := range measureNames {
+		if n == name {
+			return m, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown measure: %q", name)
+}
+
+// Collocation is a scored n-gram.
+type Collocation struct {
+	Words []string `json:"words"`
+	Count int      `json:"count"`
+	Score float64  `json:"score"`
+}
+
+// String returns the words separated by spaces.
+func (c Collocation) String() string {
+	return strings.Join(c.Words, " ")
+}
+
+// CollocationFinder counts n-grams and finds collocations.
+type CollocationFinder struct {
+	// MinCount is the minimal count of an n-gram to be a collocation, 0 means 1.
+	MinCount int
+	// Ignore, if set, skips n-grams that start or end with a word it returns true for, e.g. stop words.
+	Ignore func(word string) bool
+
+	words    map[string]int
+	bigrams  map[[2]string]int
+	trigrams map[[3]string]int
+	total    int
+}
+
+// Add counts the words and n-grams of tokens, e.g. the output of Tokenize for one sentence or document.
+// N-grams don't span calls to Add.
+func (cf *CollocationFinder) Add(tokens []string) {
+	if cf.words == nil {
+		cf.words = make(map[string]int)
+		cf.bigrams = make(map[[2]string]int)
+		cf.trigrams = make(map[[3]string]int)
+	}
+
+	for i, tok := range tokens {
+		cf.words[tok]++
+		cf.total++
+		if i+1 < len(tokens) {
+			cf.bigrams[[2]string{tok, tokens[i+1]}]++
+		}
+		if i+2 < len(tokens) {
+			cf.trigrams[[3]string{tok, tokens[i+1], tokens[i+2]}]++
+		}
+	}
+}
+
+// Bigrams returns the bigram collocations, highest score first.
+func (cf *CollocationFinder) Bigrams(m Measure) []Collocation {
+	var cols []Collocation
+	for bg, count := range cf.bigrams {
+		if !cf.keep(bg[0], bg[1], count) {
+			continue
+		}
+		score := cf.score(m, count, cf.words[bg[0]], cf.words[bg[1]])
+		cols = append(cols, Collocation{Words: bg[:], Count: count, Score: score})
+	}
+	sortCollocations(cols)
+	return cols
+}
+
+// Trigrams returns the trigram collocations, highest score first.
+// Trigrams are scored as a bigram of their first two words and their last word.
+func (cf *CollocationFinder) Trigrams(m Measure) []Collocation {
+	var cols []Collocation
+	for tg, count := range cf.trigrams {
+		if !cf.keep(tg[0], tg[2], count) {
+			continue
+		}
+		score := cf.score(m, count, cf.bigrams[[2]string{tg[0], tg[1]}], cf.words[tg[2]])
+		cols = append(cols, Collocation{Words: tg[:], Count: count, Score: score})
+	}
+	sortCollocations(cols)
+	return cols
+}
+
+func (cf *CollocationFinder) keep(first, last string, count int) bool {
+	if count < max(cf.MinCount, 1) {
+		return false
+	}
+	return cf.Ignore == nil || (!cf.Ignore(first) && !cf.Ignore(last))
+}
+
+/*
+score returns the association between x and y, which occur together n11 times, and n1x and nx1 times in total.
+The contingency table is:
+
+	         y              not y
+	x        n11            n1x - n11
+	not x    nx1 - n11      N - n1x - nx1 + n11
+*/
+func (cf *CollocationFinder) score(m Measure, n11, n1x, nx1 int) float64 {
+	n := float64(cf.total)
+	o11 := float64(n11)
+	e11 := float64(n1x) * float64(nx1) / n
+
+	switch m {
+	case PMI:
+		return math.Log2(o11 / e11)
+	case TScore:
+		return (o11 - e11) / math.Sqrt(o11)
+	}
+
+	return gSquared([4]float64{o11, float64(n1x - n11), float64(nx1 - n11), n - float64(n1x) - float64(nx1) + o11})
+}
+
+// gSquared returns the log-likelihood ratio (G²) of a 2x2 contingency table, given row by row.
+func gSquared(observed [4]float64) float64 {
+	rows := [2]float64{observed[0] + observed[1], observed[2] + observed[3]}
+	cols := [2]float64{observed[0] + observed[2], observed[1] + observed[3]}
+	n := rows[0] + rows[1]
+	g2 := 0.0
+	for i, o := range observed {
+		if o <= 0 {
+			continue
+		}
+		e := rows[i/2] * cols[i%2] / n
+		g2 += o * math.Log(o/e)
+	}
+	return 2 * g2
+}
+
+func sortCollocations(cols []Collocation) {
+	sort.Slice(cols, func(i, j int) bool {
+		if cols[i].Score != cols[j].Score {
+			return cols[i].Score > cols[j].Score
+		}
+		if cols[i].Count != cols[j].Count {
+			return cols[i].Count > cols[j].Count
+		}
+		return cols[i].String() < cols[j].String()
+	})
+}
+
+// PhraseSeparator joins the words of a phrase in a single token, e.g. "baker_street".
+const PhraseSeparator = "_"
+
+// PhraseFilter returns a Filter that joins the tokens of every collocation into a single token, e.g. "baker" "street" -> "baker_street".
+// Longer collocations are joined first.
+func PhraseFilter(collocations []Collocation) Filter {
+	phrases := make(map[string]bool)
+	maxLen := 0
+	for _, c := range collocations {
+		phrases[strings.Join(c.Words, PhraseSeparator)] = true
+		maxLen = max(maxLen, len(c.Words))
+	}
+
+	return func(tokens []string) []string {
+		out := tokens[:0]
+		for i := 0; i < len(tokens); {
+			n := 1
+			for l := min(maxLen, len(tokens)-i); l > 1; l-- {
+				if phrases[strings.Join(tokens[i:i+l], PhraseSeparator)] {
+					n = l
+					break
+				}
+			}
+			out = append(out, strings.Join(tokens[i:i+n], PhraseSeparator))
+			i += n
+		}
+		return out
+	}
+}
@@ -0,0 +1,80 @@
+package nlp
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollocationMeasures(t *testing.T) {
+	var cf CollocationFinder
+	cf.Add(strings.Fields("new york is big"))
+	cf.Add(strings.Fields("new york is old"))
+
+	var cases = []struct {
+		measure  Measure
+		expected float64
+	}{
+		{PMI, 2},
+		{TScore, 1.0607},
+		{LogLikelihood, 8.997},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.measure.String(), func(t *testing.T) {
+			cols := cf.Bigrams(tc.measure)
+			require.Len(t, cols, 4) // "new york", "york is", "is big", "is old".
+			i := 0
+			for cols[i].String() != "new york" {
+				i++
+			}
+			require.Equal(t, 2, cols[i].Count)
+			require.InDelta(t, tc.expected, cols[i].Score, 0.001)
+
+			m, err := ParseMeasure(tc.measure.String())
+			require.NoError(t, err)
+			require.Equal(t, tc.measure, m)
+		})
+	}
+
+	_, err := ParseMeasure("chi2")
+	require.Error(t, err)
+
+	cf.MinCount = 2
+	require.Len(t, cf.Bigrams(PMI), 2)
+	require.Equal(t, "new york is", cf.Trigrams(PMI)[0].String())
+}
+
+func TestCollocationsSherlock(t *testing.T) {
+	data, err := os.ReadFile("testdata/sherlock.txt")
+	require.NoError(t, err)
+
+	cf := CollocationFinder{
+		MinCount: 5,
+		Ignore:   func(word string) bool { return IsStopWord("en", word) },
+	}
+	for _, sentence := range SplitSentences(string(data)) {
+		cf.Add(Tokenize(sentence))
+	}
+
+	var top []string
+	for _, c := range cf.Bigrams(LogLikelihood)[:25] {
+		top = append(top, c.String())
+	}
+	require.Contains(t, top, "baker street")
+	require.Contains(t, top, "sherlock holme")
+}
+
+func TestPhraseFilter(t *testing.T) {
+	cols := []Collocation{
+		{Words: []string{"baker", "street"}},
+		{Words: []string{"sherlock", "holme"}},
+		{Words: []string{"mr", "sherlock", "holme"}},
+	}
+	a := Analyzer{Filters: []Filter{LowerCaseFilter, StemFilter, PhraseFilter(cols)}}
+
+	tokens := a.Tokenize("Mr. Sherlock Holmes of Baker Street met Sherlock Holmes")
+	require.Equal(t, []string{"mr_sherlock_holme", "of", "baker_street", "met", "sherlock_holme"}, tokens)
+}
@@ -0,0 +1,148 @@
+package nlp
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"nlp/stemmer"
+)
+
+/*
+Concordance.
+A concordance (or keyword in context, KWIC) lists every occurrence of a word with the words around it, one per line,
+with the keyword aligned in the middle:
+
+	   seen little of  Holmes  lately. My marriage
+	my own happiness.  Holmes  detects, I write
+
+Words are matched by their stem, so "detecting" also finds "detected" and "detects".
+*/
+
+// KWICLine is an occurrence of a keyword with its context.
+type KWICLine struct {
+	Left    string `json:"left"`
+	Keyword string `json:"keyword"`
+	Right   string `json:"right"`
+	Offset  int    `json:"offset"` // Byte offset of Keyword in the text.
+}
+
+// Concordance finds the occurrences of words in a text.
+type Concordance struct {
+	text  string
+	words [][]int          // Start and end offsets of the words in text.
+	stems map[string][]int // Stem -> indices in words.
+}
+
+// spaceRe matches runs of white space, which are shown as a single space in the context.
+var spaceRe = regexp.MustCompile(`\s+`)
+
+// NewConcordance returns a Concordance for text.
+func NewConcordance(text string) *Concordance {
+	c := &Concordance{
+		text:  text,
+		words: wordRe.FindAllStringIndex(text, -1),
+		stems: make(map[string][]int),
+	}
+	for i, loc := range c.words {
+		stem := concordanceStem(text[loc[0]:loc[1]])
+		c.stems[stem] = append(c.stems[stem], i)
+	}
+	return c
+}
+
+func concordanceStem(word string) string {
+	return stemmer.Stem(strings.ToLower(word))
+}
+
+// Find returns the occurrences of query (a single word), with up to width words of context on each side, in text order.
+func (c *Concordance) Find(query string, width int) []KWICLine {
+	width = max(width, 0)
+	var lines []KWICLine
+	for _, i := range c.stems[concordanceStem(query)] {
+		loc := c.words[i]
+		start := c.words[max(i-width, 0)][0]
+		end := c.words[min(i+width, len(c.words)-1)][1]
+		lines = append(lines, KWICLine{
+			Left:    strings.TrimSpace(spaceRe.ReplaceAllString(c.text[start:loc[0]], " ")),
+			Keyword: c.text[loc[0]:loc[1]],
+			Right:   strings.TrimSpace(spaceRe.ReplaceAllString(c.text[loc[1]:end], " ")),
+			Offset:  loc[0],
+		})
+	}
+	return lines
+}
+
+// KWICOrder is the order of concordance lines.
+type KWICOrder int
+
+const (
+	TextOrder  KWICOrder = iota // Order of occurrence.
+	LeftOrder                   // By the words before the keyword, nearest first.
+	RightOrder                  // By the words after the keyword.
+)
+
+// ParseKWICOrder returns the KWICOrder for name: "text", "left" or "right".
+func ParseKWICOrder(name string) (KWICOrder, error) {
+	switch name {
+	case "text", "":
+		return TextOrder, nil
+	case "left":
+		return LeftOrder, nil
+	case "right":
+		return RightOrder, nil
+	}
+	return TextOrder, fmt.Errorf("unknown order: %q", name)
+}
+
+// SortKWIC sorts lines in order. Context is compared word by word, ignoring case and punctuation.
+func SortKWIC(lines []KWICLine, order KWICOrder) {
+	key := func(l KWICLine) []string {
+		switch order {
+		case LeftOrder:
+			words := wordRe.FindAllString(strings.ToLower(l.Left), -1)
+			for i, j := 0, len(words)-1; i < j; i, j = i+1, j-1 {
+				words[i], words[j] = words[j], words[i]
+			}
+			return words
+		case RightOrder:
+			return wordRe.FindAllString(strings.ToLower(l.Right), -1)
+		}
+		return nil
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool {
+		if order == TextOrder {
+			return lines[i].Offset < lines[j].Offset
+		}
+		ki, kj := key(lines[i]), key(lines[j])
+		for n := 0; n < len(ki) && n < len(kj); n++ {
+			if ki[n] != kj[n] {
+				return ki[n] < kj[n]
+			}
+		}
+		return len(ki) < len(kj)
+	})
+}
+
+// WriteKWIC writes lines aligned on the keyword, one per line.
+func WriteKWIC(w io.Writer, lines []KWICLine) error {
+	leftWidth, keyWidth := 0, 0
+	for _, l := range lines {
+		leftWidth = max(leftWidth, utf8.RuneCountInString(l.Left))
+		keyWidth = max(keyWidth, utf8.RuneCountInString(l.Keyword))
+	}
+
+	for _, l := range lines {
+		pad := strings.Repeat(" ", leftWidth-utf8.RuneCountInString(l.Left))
+		keyPad := strings.Repeat(" ", keyWidth-utf8.RuneCountInString(l.Keyword))
+		line := strings.TrimRight(fmt.Sprintf("%s%s  %s%s  %s", pad, l.Left, l.Keyword, keyPad, l.Right), " ")
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package nlp
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const concordanceText = `I had seen little of Holmes lately. My marriage had drifted us away from each other.
+He was detecting crimes; I detected nothing but my own happiness. Holmes detects, I write.`
+
+func TestConcordance(t *testing.T) {
+	c := NewConcordance(concordanceText)
+
+	lines := c.Find("detecting", 2)
+	require.Equal(t, []KWICLine{
+		{Left: "He was", Keyword: "detecting", Right: "crimes; I", Offset: 92},
+		{Left: "crimes; I", Keyword: "detected", Right: "nothing but", Offset: 112},
+		{Left: "happiness. Holmes", Keyword: "detects", Right: ", I write", Offset: 158},
+	}, lines)
+
+	// Context is cut at the start and end of the text.
+	lines = c.Find("i", 3)
+	require.Equal(t, KWICLine{Left: "", Keyword: "I", Right: "had seen little", Offset: 0}, lines[0])
+	require.Equal(t, KWICLine{Left: "happiness. Holmes detects,", Keyword: "I", Right: "write", Offset: 167}, lines[len(lines)-1])
+
+	require.Nil(t, c.Find("Watson", 3))
+}
+
+func TestSortKWIC(t *testing.T) {
+	c := NewConcordance(concordanceText)
+	lines := c.Find("detect", 2)
+
+	SortKWIC(lines, RightOrder)
+	require.Equal(t, []string{"detecting", "detects", "detected"}, keywords(lines)) // crimes, i, nothing.
+
+	SortKWIC(lines, LeftOrder)
+	require.Equal(t, []string{"detects", "detected", "detecting"}, keywords(lines)) // holmes, i, was.
+
+	SortKWIC(lines, TextOrder)
+	require.Equal(t, []string{"detecting", "detected", "detects"}, keywords(lines))
+
+	_, err := ParseKWICOrder("middle")
+	require.Error(t, err)
+}
+
+func keywords(lines []KWICLine) []string {
+	var words []string
+	for _, l := range lines {
+		words = append(words, l.Keyword)
+	}
+	return words
+}
+
+func TestWriteKWIC(t *testing.T) {
+	lines := NewConcordance(concordanceText).Find("holmes", 3)
+
+	var buf bytes.Buffer
+	err := WriteKWIC(&buf, lines)
+	require.NoError(t, err)
+	expected := "" +
+		"   seen little of  Holmes  lately. My marriage\n" +
+		"my own happiness.  Holmes  detects, I write\n"
+	require.Equal(t, expected, buf.String())
+}
+
+func TestConcordanceSherlock(t *testing.T) {
+	data, err := os.ReadFile("testdata/sherlock.txt")
+	require.NoError(t, err)
+
+	lines := NewConcordance(string(data)).Find("detecting", 5)
+	require.NotEmpty(t, lines)
+	for _, l := range lines {
+		require.Equal(t, "detect", concordanceStem(l.Keyword))
+	}
+}
@@ -0,0 +1,220 @@
+package nlp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"maps"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+/*
+Contractions.
+wordRe splits "who's" into "who" and "s", and the stemmer then turns "s" into an empty token that is dropped.
+The same happens to "don't" ("don" and "t"), so negation is lost.
+ContractionExpander expands contractions in the text before it's tokenized: "who's" -> "who is", "don't" -> "do not".
+
+Some contractions are ambiguous, and are resolved by looking at the word that follows:
+	- "'d" is "would", or "had" before a past participle ("I'd seen it").
+	- "'s" is "is" after a pronoun, or "has" before an irregular past participle ("he's been", but "he's tired").
+	  After any other word it's a possessive ("Holmes's pipe"), and is dropped.
+*/
+
+var defaultContractions = map[string]string{
+	"ain't":  "is not",
+	"can't":  "can not",
+	"won't":  "will not",
+	"shan't": "shall not",
+	"let's":  "let us",
+	"y'all":  "you all",
+	"ma'am":  "madam",
+	"'tis":   "it is",
+	"'twas":  "it was",
+}
+
+var (
+	contractionRe = regexp.MustCompile(`(?i)(?:\b[a-z]+(?:['’][a-z]+)+|['’]\b[a-z]+)\b`)
+	nextWordRe    = regexp.MustCompile(`^\W*([a-zA-Z]+)`)
+
+	// sSubjects are the words after which "'s" is "is" or "has" rather than a possessive.
+	sSubjects = map[string]bool{
+		"he": true, "she": true, "it": true, "that": true, "this": true, "there": true, "here": true,
+		"what": true, "who": true, "where": true, "when": true, "why": true, "how": true,
+	}
+
+	// participles are common irregular past participles, regular ones end with "ed".
+	participles = map[string]bool{
+		"been": true, "got": true, "gotten": true, "had": true, "gone": true, "done": true, "seen": true,
+		"made": true, "taken": true, "given": true, "known": true, "come": true, "become": true,
+		"left": true, "found": true, "told": true, "thought": true, "heard": true, "said": true,
+		"agreed": true, "freed": true, "guaranteed": true, // Not caught by the "-ed" rule, see isParticiple.
+		"better": true, // "I'd better", "you'd better".
+	}
+
+	// nonParticiples are verbs ending in "-ed" that are not past participles ("I'd shed a tear").
+	nonParticiples = map[string]bool{
+		"shed": true, "embed": true, "imbed": true,
+	}
+
+	clitics = map[string]string{
+		"'re": "are",
+		"'ve": "have",
+		"'ll": "will",
+		"'m":  "am",
+	}
+)
+
+// ContractionExpander expands English contractions.
+type ContractionExpander struct {
+	// Dictionary maps lower case contractions to their expansion, it's checked before the rules.
+	Dictionary map[string]string
+}
+
+// NewContractionExpander returns a ContractionExpander with the default dictionary.
+func NewContractionExpander() *ContractionExpander {
+	return &ContractionExpander{
+		Dictionary: maps.Clone(defaultContractions),
+	}
+}
+
+// Load adds entries to the dictionary from r.
+// Every line is a contraction followed by its expansion, e.g. "y'know you know". Empty lines and lines starting with "#" are ignored.
+func (c *ContractionExpander) Load(r io.Reader) error {
+	if c.Dictionary == nil {
+		c.Dictionary = make(map[string]string)
+	}
+
+	s := bufio.NewScanner(r)
+	lnum := 0
+	for s.Scan() {
+		lnum++
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return fmt.Errorf("%d: missing expansion for %q", lnum, fields[0])
+		}
+		key := strings.ToLower(strings.ReplaceAll(fields[0], "’", "'"))
+		c.Dictionary[key] = strings.Join(fields[1:], " ")
+	}
+	return s.Err()
+}
+
+// Expand returns text with the contractions in it expanded.
+func (c *ContractionExpander) Expand(text string) string {
+	var b strings.Builder
+	pos := 0
+	for _, loc := range contractionRe.FindAllStringIndex(text, -1) {
+		word := text[loc[0]:loc[1]]
+		var next string
+		if m := nextWordRe.FindStringSubmatch(text[loc[1]:]); m != nil {
+			next = strings.ToLower(m[1])
+		}
+
+		expansion, ok := c.expand(word, next)
+		if !ok {
+			continue
+		}
+		b.WriteString(text[pos:loc[0]])
+		b.WriteString(expansion)
+		pos = loc[1]
+	}
+	b.WriteString(text[pos:])
+	return b.String()
+}
+
+// CharFilter returns c.Expand as a CharFilter, to use it in an Analyzer.
+func (c *ContractionExpander) CharFilter() CharFilter {
+	return c.Expand
+}
+
+// expand returns the expansion of word. Dictionary expansions take the case of word, rule expansions keep the base
+// as it is ("NASA's" -> "NASA", "McDonald's" -> "McDonald") and only case the words they add ("DON'T" -> "DO NOT").
+func (c *ContractionExpander) expand(word, next string) (string, bool) {
+	lower := strings.ToLower(strings.ReplaceAll(word, "’", "'"))
+	if exp, ok := c.Dictionary[lower]; ok {
+		return matchCase(exp, word), true
+	}
+
+	// The apostrophe in word, which can be "'" or "’" (3 bytes).
+	i := strings.LastIndexAny(word, "'’")
+	if i <= 0 {
+		return "", false
+	}
+	base, clitic := word[:i], lower[strings.LastIndexByte(lower, '\''):]
+	added := func(s string) string {
+		return base + " " + matchAllCaps(s, word)
+	}
+
+	if clitic == "'t" && strings.HasSuffix(strings.ToLower(base), "n") {
+		base = base[:len(base)-1]
+		return added("not"), true
+	}
+	if exp, ok := clitics[clitic]; ok {
+		return added(exp), true
+	}
+
+	switch clitic {
+	case "'d":
+		if isParticiple(next) {
+			return added("had"), true
+		}
+		return added("would"), true
+	case "'s":
+		if !sSubjects[strings.ToLower(base)] {
+			return base, true // Possessive.
+		}
+		if participles[next] && next != "better" {
+			return added("has"), true
+		}
+		return added("is"), true
+	}
+	return "", false
+}
+
+// isParticiple reports whether word is (probably) a past participle.
+// Words ending in "-eed" ("need", "proceed", "succeed") are verbs, unless they're in participles.
+func isParticiple(word string) bool {
+	if participles[word] {
+		return true
+	}
+	if nonParticiples[word] || strings.HasSuffix(word, "eed") {
+		return false
+	}
+	return len(word) > 3 && strings.HasSuffix(word, "ed")
+}
+
+// matchAllCaps returns s in upper case if orig is all upper case ("DON'T"), s otherwise.
+func matchAllCaps(s, orig string) string {
+	if strings.ToUpper(orig) == orig && strings.ToLower(orig) != orig {
+		return strings.ToUpper(s)
+	}
+	return s
+}
+
+// matchCase returns s in the case of orig: all upper case, capitalized, or as is.
+func matchCase(s, orig string) string {
+	letters := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) {
+			return r
+		}
+		return -1
+	}, orig)
+
+	switch {
+	case len(letters) > 1 && strings.ToUpper(letters) == letters:
+		return strings.ToUpper(s)
+	case letters != "":
+		first, _ := utf8.DecodeRuneInString(letters)
+		if unicode.IsUpper(first) {
+			r, size := utf8.DecodeRuneInString(s)
+			return string(unicode.ToUpper(r)) + s[size:]
+		}
+	}
+	return s
+}
@@ -0,0 +1,66 @@
+package nlp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestContractionExpander(t *testing.T) {
+	var cases = []struct {
+		text     string
+		expected string
+	}{
+		{"Who's on first?", "Who is on first?"},
+		{"I don't know.", "I do not know."},
+		{"I DON'T KNOW.", "I DO NOT KNOW."},
+		{"I'd like that.", "I would like that."},
+		{"I'd seen it before.", "I had seen it before."},
+		{"I'd finished it.", "I had finished it."},
+		{"I'd need more.", "I would need more."},
+		{"I'd proceed with care.", "I would proceed with care."},
+		{"I'd succeed.", "I would succeed."},
+		{"She'd agreed to it.", "She had agreed to it."},
+		{"You’d better go.", "You had better go."},
+		{"He's been here, and he's tired.", "He has been here, and he is tired."},
+		{"Holmes's pipe", "Holmes pipe"},
+		{"We can't, they won't.", "We can not, they will not."},
+		{"They're sure we'll come, I've heard, I'm told.", "They are sure we will come, I have heard, I am told."},
+		{"Let's go at six o'clock.", "Let us go at six o'clock."},
+		{"NASA's budget", "NASA budget"},
+		{"McDonald's menu", "McDonald menu"},
+		{"iPhone's screen", "iPhone screen"},
+		{"It's JSON's fault", "It is JSON fault"},
+		{"IT'S JSON'S FAULT", "IT IS JSON FAULT"},
+		{"I'D LIKE THAT, WE'RE SURE.", "I WOULD LIKE THAT, WE ARE SURE."},
+		{"They DON'T know", "They DO NOT know"},
+		{"McDonald’s isn’t open", "McDonald is not open"},
+	}
+
+	c := NewContractionExpander()
+	for _, tc := range cases {
+		t.Run(tc.text, func(t *testing.T) {
+			require.Equal(t, tc.expected, c.Expand(tc.text))
+		})
+	}
+}
+
+func TestContractionExpanderLoad(t *testing.T) {
+	c := NewContractionExpander()
+	err := c.Load(strings.NewReader("# Slang\ny'know you know\n\nc'mon come on\n"))
+	require.NoError(t, err)
+	require.Equal(t, "You all know, you know?", c.Expand("Y'all know, y'know?"))
+	require.Equal(t, "It is late, come on", c.Expand("'Tis late, c'mon"))
+
+	err = c.Load(strings.NewReader("y'know\n"))
+	require.Error(t, err)
+}
+
+func TestContractionAnalyzer(t *testing.T) {
+	a := Analyzer{
+		CharFilters: []CharFilter{NewContractionExpander().CharFilter()},
+		Filters:     []Filter{LowerCaseFilter},
+	}
+	require.Equal(t, []string{"i", "do", "not", "like", "it"}, a.Tokenize("I don't like it"))
+}
@@ -0,0 +1,189 @@
+package nlp
+
+import (
+	"math"
+	"sort"
+)
+
+/*
+Corpus statistics.
+A few numbers describe a corpus better than a list of its most frequent words:
+	- Tokens are the words of the text, types are the distinct tokens.
+	  The type-token ratio (types/tokens) measures the lexical variety, but it goes down as the corpus grows.
+	- Hapax legomena are the types that occur only once, usually about half of them.
+	- Zipf's law: the frequency of a type is proportional to 1/rank^s, with s close to 1.
+	  On a log-log plot of frequency against rank it's a line with a slope of about -1.
+	- Heaps' law: the number of types grows as K*tokens^β, with β between 0.4 and 0.7 for English.
+	  That's why there's always a new word around the corner, and why vocabularies need an unknown token.
+Both laws are fitted with a least squares regression on the logarithms.
+*/
+
+// CorpusStats are the statistics of a corpus, see CorpusCounter.
+type CorpusStats struct {
+	Documents         int         `json:"documents"`
+	Sentences         int         `json:"sentences"`
+	Tokens            int         `json:"tokens"`
+	Types             int         `json:"types"`
+	TypeTokenRatio    float64     `json:"type_token_ratio"`
+	HapaxLegomena     int         `json:"hapax_legomena"`
+	AvgWordLength     float64     `json:"avg_word_length"`     // Letters per word, before stemming.
+	AvgSentenceLength float64     `json:"avg_sentence_length"` // Tokens per sentence.
+	Zipf              ZipfFit     `json:"zipf"`
+	Heaps             HeapsFit    `json:"heaps"`
+	Top               []WordCount `json:"top"` // Most frequent tokens.
+}
+
+// ZipfFit is the fit of log(frequency) = Intercept + Slope*log(rank).
+type ZipfFit struct {
+	Slope     float64 `json:"slope"`
+	Intercept float64 `json:"intercept"`
+	R2        float64 `json:"r2"`
+}
+
+// HeapsFit is the fit of types = K*tokens^Beta, and the points it was fitted on.
+type HeapsFit struct {
+	K      float64       `json:"k"`
+	Beta   float64       `json:"beta"`
+	R2     float64       `json:"r2"`
+	Growth []GrowthPoint `json:"growth"`
+}
+
+// GrowthPoint is the number of types after a number of tokens.
+type GrowthPoint struct {
+	Tokens int `json:"tokens"`
+	Types  int `json:"types"`
+}
+
+// WordCount is a token and its number of occurrences.
+type WordCount struct {
+	Word  string `json:"word"`
+	Count int    `json:"count"`
+}
+
+// growthStep is the ratio between the token counts of two growth points.
+const growthStep = 1.2
+
+// CorpusCounter counts the tokens, types and sentences of documents, with Tokenize and SplitSentences.
+type CorpusCounter struct {
+	counts    map[string]int
+	documents int
+	sentences int
+	tokens    int
+	words     int
+	letters   int
+	growth    []GrowthPoint
+	next      int // Number of tokens of the next growth point.
+}
+
+// Add counts a document.
+func (cc *CorpusCounter) Add(text string) {
+	if cc.counts == nil {
+		cc.counts = make(map[string]int)
+		cc.next = 1
+	}
+
+	cc.documents++
+	for _, sentence := range SplitSentences(text) {
+		tokens := Tokenize(sentence)
+		if len(tokens) == 0 {
+			continue
+		}
+		cc.sentences++
+		for _, w := range wordRe.FindAllString(sentence, -1) {
+			cc.words++
+			cc.letters += len(w)
+		}
+		for _, tok := range tokens {
+			cc.counts[tok]++
+			cc.tokens++
+			if cc.tokens == cc.next {
+				cc.growth = append(cc.growth, GrowthPoint{Tokens: cc.tokens, Types: len(cc.counts)})
+				cc.next = max(cc.next+1, int(float64(cc.next)*growthStep))
+			}
+		}
+	}
+}
+
+// Stats returns the statistics of the documents added so far, with the top n tokens.
+func (cc *CorpusCounter) Stats(n int) CorpusStats {
+	st := CorpusStats{
+		Documents: cc.documents,
+		Sentences: cc.sentences,
+		Tokens:    cc.tokens,
+		Types:     len(cc.counts),
+	}
+	if cc.tokens == 0 {
+		return st
+	}
+
+	st.TypeTokenRatio = float64(st.Types) / float64(st.Tokens)
+	st.AvgWordLength = float64(cc.letters) / float64(cc.words)
+	st.AvgSentenceLength = float64(cc.tokens) / float64(cc.sentences)
+
+	ranked := make([]WordCount, 0, len(cc.counts))
+	for w, c := range cc.counts {
+		ranked = append(ranked, WordCount{w, c})
+		if c == 1 {
+			st.HapaxLegomena++
+		}
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Count != ranked[j].Count {
+			return ranked[i].Count > ranked[j].Count
+		}
+		return ranked[i].Word < ranked[j].Word
+	})
+	st.Top = ranked[:min(max(n, 0), len(ranked))]
+
+	xs, ys := make([]float64, len(ranked)), make([]float64, len(ranked))
+	for i, wc := range ranked {
+		xs[i], ys[i] = math.Log(float64(i+1)), math.Log(float64(wc.Count))
+	}
+	st.Zipf.Slope, st.Zipf.Intercept, st.Zipf.R2 = fitLine(xs, ys)
+
+	growth := cc.growth
+	if last := growth[len(growth)-1]; last.Tokens != cc.tokens {
+		growth = append(growth[:len(growth):len(growth)], GrowthPoint{Tokens: cc.tokens, Types: len(cc.counts)})
+	}
+	xs, ys = make([]float64, len(growth)), make([]float64, len(growth))
+	for i, p := range growth {
+		xs[i], ys[i] = math.Log(float64(p.Tokens)), math.Log(float64(p.Types))
+	}
+	beta, logK, r2 := fitLine(xs, ys)
+	st.Heaps = HeapsFit{K: math.Exp(logK), Beta: beta, R2: r2, Growth: growth}
+
+	return st
+}
+
+// fitLine returns the least squares fit of y = intercept + slope*x, and its coefficient of determination.
+func fitLine(xs, ys []float64) (slope, intercept, r2 float64) {
+	n := float64(len(xs))
+	if n < 2 {
+		return 0, 0, 0
+	}
+
+	var sx, sy float64
+	for i := range xs {
+		sx += xs[i]
+		sy += ys[i]
+	}
+	mx, my := sx/n, sy/n
+
+	var sxx, sxy, syy float64
+	for i := range xs {
+		dx, dy := xs[i]-mx, ys[i]-my
+		sxx += dx * dx
+		sxy += dx * dy
+		syy += dy * dy
+	}
+	if sxx == 0 {
+		return 0, my, 0
+	}
+
+	slope = sxy / sxx
+	intercept = my - slope*mx
+	if syy == 0 {
+		return slope, intercept, 1 // All the points are on a horizontal line.
+	}
+	return slope, intercept, sxy * sxy / (sxx * syy)
+}
@@ -0,0 +1,67 @@
+package nlp
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCorpusCounter(t *testing.T) {
+	var cc CorpusCounter
+	cc.Add("The cat sat. The dog sat on the mat!")
+	cc.Add("Cats run.")
+
+	st := cc.Stats(2)
+	require.Equal(t, 2, st.Documents)
+	require.Equal(t, 3, st.Sentences)
+	require.Equal(t, 11, st.Tokens)
+	require.Equal(t, 7, st.Types) // the cat sat dog on mat run.
+	require.InDelta(t, 7.0/11, st.TypeTokenRatio, 1e-9)
+	require.Equal(t, 4, st.HapaxLegomena) // dog on mat run.
+	require.InDelta(t, 3, st.AvgWordLength, 1e-9)
+	require.InDelta(t, 11.0/3, st.AvgSentenceLength, 1e-9)
+	require.Equal(t, []WordCount{{"the", 3}, {"cat", 2}}, st.Top)
+
+	last := st.Heaps.Growth[len(st.Heaps.Growth)-1]
+	require.Equal(t, GrowthPoint{Tokens: 11, Types: 7}, last)
+
+	var empty CorpusCounter
+	require.Equal(t, CorpusStats{}, empty.Stats(10))
+}
+
+func TestCorpusZipf(t *testing.T) {
+	// Word i occurs 840/i times, an exact Zipf distribution.
+	var sb strings.Builder
+	words := strings.Fields("alpha bravo charlie delta echo foxtrot golf")
+	for i, w := range words {
+		for range 840 / (i + 1) {
+			sb.WriteString(w + " ")
+		}
+	}
+
+	var cc CorpusCounter
+	cc.Add(sb.String())
+	st := cc.Stats(0)
+	require.InDelta(t, -1, st.Zipf.Slope, 1e-3)
+	require.InDelta(t, 1, st.Zipf.R2, 1e-6)
+	require.Empty(t, st.Top)
+}
+
+func TestCorpusSherlock(t *testing.T) {
+	data, err := os.ReadFile("testdata/sherlock.txt")
+	require.NoError(t, err)
+
+	var cc CorpusCounter
+	cc.Add(string(data))
+	st := cc.Stats(10)
+
+	require.Equal(t, "the", st.Top[0].Word)
+	require.Less(t, st.Zipf.Slope, -0.7)
+	require.Greater(t, st.Zipf.Slope, -1.5)
+	require.Greater(t, st.Heaps.Beta, 0.3)
+	require.Less(t, st.Heaps.Beta, 0.9)
+	require.Greater(t, st.Heaps.R2, 0.9)
+	require.Greater(t, st.HapaxLegomena, st.Types/3)
+}
@@ -0,0 +1,322 @@
+package nlp
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+/*
+Word diff.
+Line based diffs are useless for prose: a paragraph is often a single line, and one changed word marks all of it.
+Diff compares texts word by word with the Myers algorithm, which finds the shortest edit script:
+the fewest words to delete and insert to go from a to b.
+It explores the edits in order of the number of differences d, and for every diagonal k (x - y) keeps the furthest
+point it reached with d differences, following runs of equal words ("snakes") for free.
+That's O((N+M)*D) time, fast when the texts are similar, which is the usual case for revisions.
+To keep the space linear, it searches forward and backward at the same time to find the middle of the edit script,
+and diffs the halves on each side of it recursively.
+
+The tokens are words, spaces and punctuation, so the runs put together give back both texts exactly.
+*/
+
+// diffTokenRe matches words (with apostrophes), spaces, or a punctuation character.
+var diffTokenRe = regexp.MustCompile(`[\p{L}\p{M}\p{N}]+(?:['’][\p{L}\p{M}\p{N}]+)*|\s+|[^\s\p{L}\p{M}\p{N}]`)
+
+// DiffOp is the operation of a DiffRun.
+type DiffOp int
+
+const (
+	DiffEqual DiffOp = iota
+	DiffDelete
+	DiffInsert
+)
+
+var diffOpNames = [...]string{"equal", "delete", "insert"}
+
+// String implements fmt.Stringer.
+func (op DiffOp) String() string {
+	if op < 0 || int(op) >= len(diffOpNames) {
+		return fmt.Sprintf("DiffOp(%d)", int(op))
+	}
+	return diffOpNames[op]
+}
+
+// MarshalText implements encoding.TextMarshaler, so operations are encoded as names in JSON.
+func (op DiffOp) MarshalText() ([]byte, error) {
+	return []byte(op.String()), nil
+}
+
+// DiffRun is a run of tokens with the same operation.
+// Its offsets are byte offsets in both texts, a deletion has an empty range in b and an insertion an empty range in a.
+type DiffRun struct {
+	Op     DiffOp `json:"op"`
+	Text   string `json:"text"` // From a, except for insertions.
+	AStart int    `json:"a_start"`
+	AEnd   int    `json:"a_end"`
+	BStart int    `json:"b_start"`
+	BEnd   int    `json:"b_end"`
+}
+
+// DiffOptions are the options of Diff.
+type DiffOptions struct {
+	// Split returns the [start, end] offsets of the tokens of a text, e.g. a regexp's FindAllStringIndex with -1.
+	// Text between tokens is ignored. The default splits words, spaces and punctuation.
+	Split func(text string) [][]int
+	// Normalize returns the token to compare, e.g. strings.ToLower to ignore case, or a stemmer.
+	Normalize func(token string) string
+}
+
+// Diff compares a and b token by token, and returns the equal, deleted and inserted runs, in order.
+// Spaces between two changes are part of the change, so that "the quick" -> "a slow" is one deletion and one insertion.
+func Diff(a, b string, opts DiffOptions) []DiffRun {
+	split := opts.Split
+	if split == nil {
+		split = func(text string) [][]int { return diffTokenRe.FindAllStringIndex(text, -1) }
+	}
+	aSpans, bSpans := split(a), split(b)
+	aKeys, bKeys := diffKeys(a, aSpans, opts.Normalize), diffKeys(b, bSpans, opts.Normalize)
+
+	var runs []DiffRun
+	add := func(op DiffOp, i, j int) { // Token a[i] or b[j].
+		var run DiffRun
+		switch op {
+		case DiffEqual:
+			run = DiffRun{Op: op, AStart: aSpans[i][0], AEnd: aSpans[i][1], BStart: bSpans[j][0], BEnd: bSpans[j][1]}
+		case DiffDelete:
+			pos := diffOffset(bSpans, j, len(b))
+			run = DiffRun{Op: op, AStart: aSpans[i][0], AEnd: aSpans[i][1], BStart: pos, BEnd: pos}
+		case DiffInsert:
+			pos := diffOffset(aSpans, i, len(a))
+			run = DiffRun{Op: op, AStart: pos, AEnd: pos, BStart: bSpans[j][0], BEnd: bSpans[j][1]}
+		}
+		if n := len(runs); n > 0 && runs[n-1].Op == op {
+			runs[n-1].AEnd, runs[n-1].BEnd = run.AEnd, run.BEnd
+			return
+		}
+		runs = append(runs, run)
+	}
+	myersDiff(aKeys, bKeys, add)
+
+	runs = mergeDiffChanges(runs, a)
+	for i := range runs {
+		if runs[i].Op == DiffInsert {
+			runs[i].Text = b[runs[i].BStart:runs[i].BEnd]
+		} else {
+			runs[i].Text = a[runs[i].AStart:runs[i].AEnd]
+		}
+	}
+	return runs
+}
+
+// diffKeys returns the tokens of text, normalized.
+func diffKeys(text string, spans [][]int, normalize func(string) string) []string {
+	keys := make([]string, len(spans))
+	for i, s := range spans {
+		keys[i] = text[s[0]:s[1]]
+		if normalize != nil {
+			keys[i] = normalize(keys[i])
+		}
+	}
+	return keys
+}
+
+// diffOffset returns the offset of the i-th token, or the end of the text.
+func diffOffset(spans [][]int, i, end int) int {
+	if i < len(spans) {
+		return spans[i][0]
+	}
+	return end
+}
+
+// myersDiff calls add for every token of the shortest edit script from a to b, in order:
+// DiffEqual with both indexes, DiffDelete with the index in a and DiffInsert with the index in b
+// (the other index is where the token is in the other text).
+func myersDiff(a, b []string, add func(op DiffOp, i, j int)) {
+	var vf, vb []int // Reused by all the middleSnake calls.
+	var diff func(a0, a1, b0, b1 int)
+	diff = func(a0, a1, b0, b1 int) {
+		// The common prefix and suffix are equal, no need to search them.
+		for a0 < a1 && b0 < b1 && a[a0] == b[b0] {
+			add(DiffEqual, a0, b0)
+			a0, b0 = a0+1, b0+1
+		}
+		suffix := 0
+		for a0 < a1-suffix && b0 < b1-suffix && a[a1-1-suffix] == b[b1-1-suffix] {
+			suffix++
+		}
+		a1, b1 = a1-suffix, b1-suffix
+
+		switch {
+		case a0 == a1:
+			for j := b0; j < b1; j++ {
+				add(DiffInsert, a0, j)
+			}
+		case b0 == b1:
+			for i := a0; i < a1; i++ {
+				add(DiffDelete, i, b0)
+			}
+		default:
+			var x, y, u, v int
+			x, y, u, v, vf, vb = middleSnake(a[a0:a1], b[b0:b1], vf, vb)
+			diff(a0, a0+x, b0, b0+y)
+			for i := range u - x {
+				add(DiffEqual, a0+x+i, b0+y+i)
+			}
+			diff(a0+u, a1, b0+v, b1)
+		}
+
+		for i := range suffix {
+			add(DiffEqual, a1+i, b1+i)
+		}
+	}
+	diff(0, len(a), 0, len(b))
+}
+
+// middleSnake returns the snake (x, y) -> (u, v) in the middle of the shortest edit script from a to b,
+// searching forward from the start and backward from the end at the same time until they meet.
+// The edit script goes through it, so the halves before and after it can be diffed separately,
+// which needs O(N+M) space instead of the O(D²) of keeping the furthest points of every round.
+// vf and vb are buffers for the furthest points, grown as needed and returned for reuse.
+func middleSnake(a, b []string, vf, vb []int) (x, y, u, v int, _, _ []int) {
+	n, m := len(a), len(b)
+	delta := n - m
+	odd := delta%2 != 0
+	maxD := (n + m + 1) / 2
+
+	// vf[offset+k] is the furthest x on diagonal k (x - y) going forward.
+	// vb[offset+c] is the furthest distance from the end on diagonal c going backward, c = delta - k.
+	offset := maxD + 1
+	if size := 2*offset + 1; len(vf) < size {
+		vf, vb = make([]int, size), make([]int, size)
+	}
+	vf[offset+1], vb[offset+1] = 0, 0
+
+	for d := 0; d <= maxD; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && vf[offset+k-1] < vf[offset+k+1]) {
+				x = vf[offset+k+1] // Down: insertion.
+			} else {
+				x = vf[offset+k-1] + 1 // Right: deletion.
+			}
+			y := x - k
+			x0, y0 := x, y
+			for x < n && y < m && a[x] == b[y] {
+				x, y = x+1, y+1
+			}
+			vf[offset+k] = x
+			if c := delta - k; odd && c >= -(d-1) && c <= d-1 && x+vb[offset+c] >= n {
+				return x0, y0, x, y, vf, vb
+			}
+		}
+		for c := -d; c <= d; c += 2 {
+			var x int
+			if c == -d || (c != d && vb[offset+c-1] < vb[offset+c+1]) {
+				x = vb[offset+c+1]
+			} else {
+				x = vb[offset+c-1] + 1
+			}
+			y := x - c
+			x0, y0 := x, y
+			for x < n && y < m && a[n-1-x] == b[m-1-y] {
+				x, y = x+1, y+1
+			}
+			vb[offset+c] = x
+			if k := delta - c; !odd && k >= -d && k <= d && x+vf[offset+k] >= n {
+				return n - x, m - y, n - x0, m - y0, vf, vb
+			}
+		}
+	}
+	panic("unreachable: the forward and backward searches always meet")
+}
+
+// mergeDiffChanges turns the spaces between two changes into changes, and merges the consecutive changes
+// into one deletion followed by one insertion.
+func mergeDiffChanges(runs []DiffRun, a string) []DiffRun {
+	isChange := func(i int) bool { return i >= 0 && i < len(runs) && runs[i].Op != DiffEqual }
+
+	var out []DiffRun
+	for i := 0; i < len(runs); {
+		if !isChange(i) {
+			out = append(out, runs[i])
+			i++
+			continue
+		}
+
+		// A block of changes, and of spaces between changes.
+		del := DiffRun{Op: DiffDelete, AStart: runs[i].AStart, BStart: runs[i].BStart}
+		j := i
+		for j < len(runs) && (isChange(j) || (isChange(j+1) && strings.TrimFunc(a[runs[j].AStart:runs[j].AEnd], unicode.IsSpace) == "")) {
+			j++
+		}
+		del.AEnd, del.BEnd = runs[j-1].AEnd, del.BStart
+		ins := DiffRun{Op: DiffInsert, AStart: del.AEnd, AEnd: del.AEnd, BStart: del.BStart, BEnd: runs[j-1].BEnd}
+		if del.AStart < del.AEnd {
+			out = append(out, del)
+		}
+		if ins.BStart < ins.BEnd {
+			out = append(out, ins)
+		}
+		i = j
+	}
+	return out
+}
+
+// DiffFormat is an output format of WriteDiff.
+type DiffFormat int
+
+const (
+	DiffText DiffFormat = iota // "[-deleted-]{+inserted+}", like git diff --word-diff=plain.
+	DiffANSI                   // Deletions in red and insertions in green, for terminals.
+	DiffHTML                   // <del> and <ins> elements, the text is escaped.
+)
+
+// ParseDiffFormat returns the DiffFormat for name: "text", "ansi" or "html".
+func ParseDiffFormat(name string) (DiffFormat, error) {
+	switch name {
+	case "text", "":
+		return DiffText, nil
+	case "ansi":
+		return DiffANSI, nil
+	case "html":
+		return DiffHTML, nil
+	}
+	return DiffText, fmt.Errorf("unknown diff format: %q", name)
+}
+
+// diffMarkers are the strings around deletions and insertions in every format.
+var diffMarkers = map[DiffFormat][4]string{
+	DiffText: {"[-", "-]", "{+", "+}"},
+	DiffANSI: {"\x1b[31;9m", "\x1b[0m", "\x1b[32m", "\x1b[0m"}, // Red and struck through, green.
+	DiffHTML: {"<del>", "</del>", "<ins>", "</ins>"},
+}
+
+// WriteDiff writes runs in format.
+func WriteDiff(w io.Writer, runs []DiffRun, format DiffFormat) error {
+	markers, ok := diffMarkers[format]
+	if !ok {
+		return fmt.Errorf("unknown diff format: %d", format)
+	}
+
+	var sb strings.Builder
+	for _, run := range runs {
+		text := run.Text
+		if format == DiffHTML {
+			text = html.EscapeString(text)
+		}
+		switch run.Op {
+		case DiffEqual:
+			sb.WriteString(text)
+		case DiffDelete:
+			sb.WriteString(markers[0] + text + markers[1])
+		case DiffInsert:
+			sb.WriteString(markers[2] + text + markers[3])
+		}
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
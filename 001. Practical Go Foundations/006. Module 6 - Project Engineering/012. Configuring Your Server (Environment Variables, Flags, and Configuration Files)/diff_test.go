@@ -0,0 +1,194 @@
+package nlp
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func diffString(t *testing.T, runs []DiffRun) string {
+	var sb strings.Builder
+	require.NoError(t, WriteDiff(&sb, runs, DiffText))
+	return sb.String()
+}
+
+func TestDiff(t *testing.T) {
+	var cases = []struct {
+		a, b     string
+		expected string
+	}{
+		{"the same text", "the same text", "the same text"},
+		{"", "new text", "{+new text+}"},
+		{"old text", "", "[-old text-]"},
+		{"I have no doubt.", "I have little doubt.", "I have [-no-]{+little+} doubt."},
+		{"the quick brown fox", "a slow brown fox", "[-the quick-]{+a slow+} brown fox"},
+		{"Holmes smiled.", "Holmes smiled, again.", "Holmes smiled{+, again+}."},
+		{"It isn't true", "It is true", "It [-isn't-]{+is+} true"},
+		{"one two three four", "one three four five", "one [-two -]three four{+ five+}"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.a+"/"+tc.b, func(t *testing.T) {
+			require.Equal(t, tc.expected, diffString(t, Diff(tc.a, tc.b, DiffOptions{})))
+		})
+	}
+}
+
+func TestDiffOffsets(t *testing.T) {
+	data, err := os.ReadFile("testdata/sherlock.txt")
+	require.NoError(t, err)
+	words := strings.Fields(string(data))[:500]
+
+	rng := rand.New(rand.NewPCG(1, 2))
+	for n := range 20 {
+		edited := append([]string(nil), words...)
+		for range n {
+			i := rng.IntN(len(edited))
+			switch rng.IntN(3) {
+			case 0:
+				edited = append(edited[:i], edited[i+1:]...)
+			case 1:
+				edited = append(edited[:i], append([]string{"inserted"}, edited[i:]...)...)
+			case 2:
+				edited[i] = "replaced"
+			}
+		}
+		a, b := strings.Join(words, " "), strings.Join(edited, " ")
+		runs := Diff(a, b, DiffOptions{})
+
+		// Both texts are rebuilt from the runs.
+		var sa, sb strings.Builder
+		for i, run := range runs {
+			if i > 0 {
+				require.NotEqual(t, runs[i-1].Op, run.Op)
+			}
+			if run.Op != DiffInsert {
+				require.Equal(t, run.Text, a[run.AStart:run.AEnd])
+				sa.WriteString(run.Text)
+			}
+			if run.Op != DiffDelete {
+				require.Equal(t, run.Text, b[run.BStart:run.BEnd], fmt.Sprintf("run %d", i))
+				sb.WriteString(b[run.BStart:run.BEnd])
+			}
+		}
+		require.Equal(t, a, sa.String())
+		require.Equal(t, b, sb.String())
+	}
+}
+
+func TestMyersDiffShortest(t *testing.T) {
+	// The length of the longest common subsequence, by dynamic programming.
+	lcs := func(a, b []string) int {
+		prev, cur := make([]int, len(b)+1), make([]int, len(b)+1)
+		for i := range a {
+			for j := range b {
+				if a[i] == b[j] {
+					cur[j+1] = prev[j] + 1
+				} else {
+					cur[j+1] = max(prev[j+1], cur[j])
+				}
+			}
+			prev, cur = cur, prev
+		}
+		return prev[len(b)]
+	}
+
+	rng := rand.New(rand.NewPCG(1, 2))
+	random := func() []string {
+		s := make([]string, rng.IntN(30))
+		for i := range s {
+			s[i] = string(rune('a' + rng.IntN(4)))
+		}
+		return s
+	}
+	for range 500 {
+		a, b := random(), random()
+		var i, j, edits int
+		myersDiff(a, b, func(op DiffOp, ai, bj int) {
+			switch op {
+			case DiffEqual:
+				require.Equal(t, a[ai], b[bj])
+				require.Equal(t, [2]int{i, j}, [2]int{ai, bj})
+				i, j = i+1, j+1
+			case DiffDelete:
+				require.Equal(t, i, ai)
+				i++
+				edits++
+			case DiffInsert:
+				require.Equal(t, j, bj)
+				j++
+				edits++
+			}
+		})
+		require.Equal(t, [2]int{len(a), len(b)}, [2]int{i, j})
+		require.Equal(t, len(a)+len(b)-2*lcs(a, b), edits, "%v %v", a, b)
+	}
+}
+
+func TestDiffLarge(t *testing.T) {
+	// Two unrelated texts: the edit script is as long as both, it must not need quadratic space.
+	var a, b strings.Builder
+	for i := range 5000 {
+		fmt.Fprintf(&a, "a%d ", i)
+		fmt.Fprintf(&b, "b%d ", i)
+	}
+	runs := Diff(a.String(), b.String(), DiffOptions{})
+	require.Len(t, runs, 3)
+	require.Equal(t, DiffDelete, runs[0].Op)
+	require.Equal(t, DiffInsert, runs[1].Op)
+	require.Equal(t, " ", runs[2].Text)
+}
+
+func TestDiffNormalize(t *testing.T) {
+	runs := Diff("Sherlock HOLMES", "sherlock holmes", DiffOptions{Normalize: strings.ToLower})
+	require.Equal(t, []DiffRun{{Op: DiffEqual, Text: "Sherlock HOLMES", AEnd: 15, BEnd: 15}}, runs)
+
+	// Line by line.
+	lines := func(text string) [][]int { return regexp.MustCompile(`(?m)^.*\n?`).FindAllStringIndex(text, -1) }
+	runs = Diff("one\ntwo\nthree\n", "one\n2\nthree\n", DiffOptions{Split: lines})
+	require.Equal(t, "one\n[-two\n-]{+2\n+}three\n", diffString(t, runs))
+}
+
+func TestWriteDiff(t *testing.T) {
+	runs := Diff("a < b", "a > b", DiffOptions{})
+	var cases = []struct {
+		format   string
+		expected string
+	}{
+		{"text", "a [-<-]{+>+} b"},
+		{"ansi", "a \x1b[31;9m<\x1b[0m\x1b[32m>\x1b[0m b"},
+		{"html", "a <del>&lt;</del><ins>&gt;</ins> b"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.format, func(t *testing.T) {
+			format, err := ParseDiffFormat(tc.format)
+			require.NoError(t, err)
+			var sb strings.Builder
+			require.NoError(t, WriteDiff(&sb, runs, format))
+			require.Equal(t, tc.expected, sb.String())
+		})
+	}
+
+	_, err := ParseDiffFormat("pdf")
+	require.Error(t, err)
+}
+
+func ExampleDiff() {
+	runs := Diff("To Sherlock Holmes she is always the woman.", "To Sherlock Holmes she was simply the woman.", DiffOptions{})
+	WriteDiff(os.Stdout, runs, DiffText)
+	fmt.Println()
+	for _, run := range runs {
+		fmt.Printf("%s %q a[%d:%d] b[%d:%d]\n", run.Op, run.Text, run.AStart, run.AEnd, run.BStart, run.BEnd)
+	}
+
+	// Output:
+	// To Sherlock Holmes she [-is always-]{+was simply+} the woman.
+	// equal "To Sherlock Holmes she " a[0:23] b[0:23]
+	// delete "is always" a[23:32] b[23:23]
+	// insert "was simply" a[32:32] b[23:33]
+	// equal " the woman." a[32:43] b[33:44]
+}
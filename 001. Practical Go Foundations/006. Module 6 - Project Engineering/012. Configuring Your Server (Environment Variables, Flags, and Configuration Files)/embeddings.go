@@ -0,0 +1,334 @@
+package nlp
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"nlp/stemmer"
+)
+
+/*
+Word embeddings.
+An embedding is a vector of numbers for a word, learned from a large corpus so that words used in the same contexts
+have close vectors: "cat" is close to "dog", far from "invoice".
+The direction between vectors means something too: king - man + woman is close to queen.
+Embeddings are trained elsewhere (e.g. GloVe or word2vec), and shared as files:
+	- Text: one word per line, followed by its vector ("cat 0.12 -0.5 ...").
+	  word2vec text files start with a header line with the number of words and the dimension, GloVe files don't.
+	- word2vec binary: the same header, then every word followed by a space and its vector as little endian float32.
+Similarity is the cosine of the angle between the vectors, from -1 (opposite) to 1 (same direction).
+*/
+
+// EmbeddingFormat is the format of an embeddings file.
+type EmbeddingFormat int
+
+const (
+	TextEmbeddings   EmbeddingFormat = iota // GloVe or word2vec text.
+	BinaryEmbeddings                        // word2vec binary.
+)
+
+// Embeddings are word vectors.
+type Embeddings struct {
+	dim     int
+	words   []string
+	ids     map[string]int
+	vectors []float32 // Vectors of words, one after the other.
+	norms   []float64
+}
+
+// Neighbor is a word and its cosine similarity to a query.
+type Neighbor struct {
+	Word       string  `json:"word"`
+	Similarity float64 `json:"similarity"`
+}
+
+// LoadEmbeddings reads embeddings in format.
+func LoadEmbeddings(r io.Reader, format EmbeddingFormat) (*Embeddings, error) {
+	br := bufio.NewReaderSize(r, 1<<16)
+	if format == BinaryEmbeddings {
+		return loadBinaryEmbeddings(br)
+	}
+	return loadTextEmbeddings(br)
+}
+
+func newEmbeddings(dim int) *Embeddings {
+	return &Embeddings{dim: dim, ids: make(map[string]int)}
+}
+
+func (e *Embeddings) add(word string, vec []float32) error {
+	if len(vec) != e.dim {
+		return fmt.Errorf("%q: %d dimensions, expected %d", word, len(vec), e.dim)
+	}
+	if _, ok := e.ids[word]; ok {
+		return fmt.Errorf("duplicate word %q", word)
+	}
+	e.ids[word] = len(e.words)
+	e.words = append(e.words, word)
+	e.vectors = append(e.vectors, vec...)
+	e.norms = append(e.norms, norm(vec))
+	return nil
+}
+
+func loadTextEmbeddings(r *bufio.Reader) (*Embeddings, error) {
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1<<20)
+	var e *Embeddings
+	lnum := 0
+	for s.Scan() {
+		lnum++
+		fields := strings.Fields(s.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if lnum == 1 && len(fields) == 2 {
+			if _, err := strconv.Atoi(fields[0]); err == nil {
+				continue // word2vec header.
+			}
+		}
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("%d: no vector", lnum)
+		}
+
+		vec := make([]float32, len(fields)-1)
+		for i, f := range fields[1:] {
+			v, err := strconv.ParseFloat(f, 32)
+			if err != nil {
+				return nil, fmt.Errorf("%d: bad number %q", lnum, f)
+			}
+			vec[i] = float32(v)
+		}
+		if e == nil {
+			e = newEmbeddings(len(vec))
+		}
+		if err := e.add(fields[0], vec); err != nil {
+			return nil, fmt.Errorf("%d: %w", lnum, err)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if e == nil {
+		return nil, errors.New("no vectors")
+	}
+	return e, nil
+}
+
+func loadBinaryEmbeddings(r *bufio.Reader) (*Embeddings, error) {
+	header, err := r.ReadString('\n')
+	if err != nil {
+		return nil, fmt.Errorf("bad header: %w", err)
+	}
+	var count, dim int
+	if _, err := fmt.Sscanf(header, "%d %d", &count, &dim); err != nil || count < 1 || dim < 1 {
+		return nil, fmt.Errorf("bad header %q", strings.TrimSpace(header))
+	}
+
+	e := newEmbeddings(dim)
+	buf := make([]byte, 4*dim)
+	for i := range count {
+		word, err := r.ReadString(' ')
+		if err != nil {
+			return nil, fmt.Errorf("word %d: %w", i+1, err)
+		}
+		word = strings.TrimLeft(word[:len(word)-1], "\n") // Some files end vectors with a new line.
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, fmt.Errorf("word %d (%q): %w", i+1, word, err)
+		}
+		vec := make([]float32, dim)
+		for j := range vec {
+			vec[j] = math.Float32frombits(binary.LittleEndian.Uint32(buf[4*j:]))
+		}
+		if err := e.add(word, vec); err != nil {
+			return nil, fmt.Errorf("word %d: %w", i+1, err)
+		}
+	}
+	return e, nil
+}
+
+func norm(vec []float32) float64 {
+	sum := 0.0
+	for _, v := range vec {
+		sum += float64(v) * float64(v)
+	}
+	return math.Sqrt(sum)
+}
+
+// Dim returns the number of dimensions of the vectors.
+func (e *Embeddings) Dim() int {
+	return e.dim
+}
+
+// Len returns the number of words.
+func (e *Embeddings) Len() int {
+	return len(e.words)
+}
+
+// Vector returns the vector of word. It's shared, don't modify it.
+func (e *Embeddings) Vector(word string) ([]float32, bool) {
+	id, ok := e.ids[word]
+	if !ok {
+		return nil, false
+	}
+	return e.vector(id), true
+}
+
+func (e *Embeddings) vector(id int) []float32 {
+	return e.vectors[id*e.dim : (id+1)*e.dim : (id+1)*e.dim]
+}
+
+// Similarity returns the cosine similarity of words a and b, false if one of them isn't known.
+func (e *Embeddings) Similarity(a, b string) (float64, bool) {
+	ia, ok := e.ids[a]
+	if !ok {
+		return 0, false
+	}
+	ib, ok := e.ids[b]
+	if !ok {
+		return 0, false
+	}
+	return e.cosine(ib, e.vector(ia), e.norms[ia]), true
+}
+
+// cosine returns the cosine similarity of the word id and vec, with its norm.
+func (e *Embeddings) cosine(id int, vec []float32, vecNorm float64) float64 {
+	if vecNorm == 0 || e.norms[id] == 0 {
+		return 0
+	}
+	dot := 0.0
+	for i, v := range e.vector(id) {
+		dot += float64(v) * float64(vec[i])
+	}
+	return dot / (vecNorm * e.norms[id])
+}
+
+// Nearest returns the n words most similar to word, false if word isn't known.
+func (e *Embeddings) Nearest(word string, n int) ([]Neighbor, bool) {
+	vec, ok := e.Vector(word)
+	if !ok {
+		return nil, false
+	}
+	return e.NearestVector(vec, n, word), true
+}
+
+// NearestVector returns the n words most similar to vec, except the words in exclude.
+// It compares vec to every word, which takes a few milliseconds for 100,000 words.
+func (e *Embeddings) NearestVector(vec []float32, n int, exclude ...string) []Neighbor {
+	if len(vec) != e.dim || n <= 0 {
+		return nil
+	}
+	vecNorm := norm(vec)
+
+	var neighbors []Neighbor
+	for id, word := range e.words {
+		if slices.Contains(exclude, word) {
+			continue
+		}
+		sim := e.cosine(id, vec, vecNorm)
+		if len(neighbors) == n && sim <= neighbors[n-1].Similarity {
+			continue
+		}
+		// Insert in order, neighbors is short.
+		i := sort.Search(len(neighbors), func(i int) bool { return neighbors[i].Similarity < sim })
+		if len(neighbors) < n {
+			neighbors = append(neighbors, Neighbor{})
+		}
+		copy(neighbors[i+1:], neighbors[i:])
+		neighbors[i] = Neighbor{Word: word, Similarity: sim}
+	}
+	return neighbors
+}
+
+// Analogy returns the n words closest to a - b + c, e.g. "king" - "man" + "woman" -> "queen".
+// It fails if one of the words isn't known.
+func (e *Embeddings) Analogy(a, b, c string, n int) ([]Neighbor, error) {
+	target := make([]float32, e.dim)
+	for i, word := range []string{a, b, c} {
+		id, ok := e.ids[word]
+		if !ok {
+			return nil, fmt.Errorf("unknown word: %q", word)
+		}
+		sign := float32(1)
+		if i == 1 {
+			sign = -1
+		}
+		// Use unit vectors, so that frequent words with long vectors don't dominate.
+		// A zero vector (some pretrained files have them) has no direction, it adds nothing.
+		if e.norms[id] == 0 {
+			continue
+		}
+		scale := sign / float32(e.norms[id])
+		for j, v := range e.vector(id) {
+			target[j] += scale * v
+		}
+	}
+	return e.NearestVector(target, n, a, b, c), nil
+}
+
+// DocumentVector returns the average of the vectors of tokens, skipping unknown tokens. It returns false if no token is known.
+// Pretrained vectors are for words, not stems: use e.Stemmed() with the output of Tokenize.
+func (e *Embeddings) DocumentVector(tokens []string) ([]float32, bool) {
+	sum := make([]float32, e.dim)
+	n := 0
+	for _, tok := range tokens {
+		vec, ok := e.Vector(tok)
+		if !ok {
+			continue
+		}
+		for i, v := range vec {
+			sum[i] += v
+		}
+		n++
+	}
+	if n == 0 {
+		return nil, false
+	}
+	for i := range sum {
+		sum[i] /= float32(n)
+	}
+	return sum, true
+}
+
+// Stemmed returns embeddings for the stems of the words, as Tokenize returns them:
+// the vector of a stem is the average of the vectors of the words it's the stem of, e.g. "detect" for "detects" and "detected".
+func (e *Embeddings) Stemmed() *Embeddings {
+	var stems []string
+	sums := make(map[string][]float32)
+	counts := make(map[string]int)
+	for id, word := range e.words {
+		if wordRe.FindString(word) != word {
+			continue // Tokenize would split it, or drop it.
+		}
+		stem := stemmer.Stem(strings.ToLower(word))
+		if stem == "" {
+			continue
+		}
+		sum, ok := sums[stem]
+		if !ok {
+			sum = make([]float32, e.dim)
+			sums[stem] = sum
+			stems = append(stems, stem)
+		}
+		for i, v := range e.vector(id) {
+			sum[i] += v
+		}
+		counts[stem]++
+	}
+
+	se := newEmbeddings(e.dim)
+	for _, stem := range stems {
+		vec := sums[stem]
+		for i := range vec {
+			vec[i] /= float32(counts[stem])
+		}
+		se.add(stem, vec) // Can't fail, stems are unique.
+	}
+	return se
+}
@@ -0,0 +1,139 @@
+package nlp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func loadTestEmbeddings(t *testing.T) *Embeddings {
+	file, err := os.Open("testdata/vectors.txt")
+	require.NoError(t, err)
+	defer file.Close()
+
+	e, err := LoadEmbeddings(file, TextEmbeddings)
+	require.NoError(t, err)
+	return e
+}
+
+func TestEmbeddings(t *testing.T) {
+	e := loadTestEmbeddings(t)
+	require.Equal(t, 10, e.Len())
+	require.Equal(t, 4, e.Dim())
+
+	vec, ok := e.Vector("queen")
+	require.True(t, ok)
+	require.Equal(t, []float32{0.9, -0.8, 0.1, 0}, vec)
+
+	sim, ok := e.Similarity("cat", "cat")
+	require.True(t, ok)
+	require.InDelta(t, 1, sim, 1e-6)
+	_, ok = e.Similarity("cat", "unicorn")
+	require.False(t, ok)
+
+	neighbors, ok := e.Nearest("cat", 2)
+	require.True(t, ok)
+	require.Len(t, neighbors, 2)
+	require.Equal(t, "dog", neighbors[0].Word)
+	require.Greater(t, neighbors[0].Similarity, neighbors[1].Similarity)
+
+	all, _ := e.Nearest("cat", 100)
+	require.Len(t, all, 9) // Without "cat".
+
+	analogy, err := e.Analogy("king", "man", "woman", 1)
+	require.NoError(t, err)
+	require.Equal(t, "queen", analogy[0].Word)
+	_, err = e.Analogy("king", "man", "unicorn", 1)
+	require.Error(t, err)
+
+	doc, ok := e.DocumentVector([]string{"apple", "unicorn", "banana"})
+	require.True(t, ok)
+	require.InDeltaSlice(t, []float32{0.05, 0, 0.05, 0.9}, doc, 1e-6)
+	_, ok = e.DocumentVector([]string{"unicorn"})
+	require.False(t, ok)
+}
+
+func TestEmbeddingsZeroVector(t *testing.T) {
+	e, err := LoadEmbeddings(strings.NewReader("king 0.9 0.8\nqueen 0.9 -0.8\nman 0.1 0.8\nunk 0 0\n"), TextEmbeddings)
+	require.NoError(t, err)
+
+	neighbors, err := e.Analogy("king", "man", "unk", 2)
+	require.NoError(t, err)
+	require.Len(t, neighbors, 1)
+	require.False(t, math.IsNaN(neighbors[0].Similarity))
+	_, err = json.Marshal(neighbors)
+	require.NoError(t, err)
+
+	sim, ok := e.Similarity("unk", "king")
+	require.True(t, ok)
+	require.Zero(t, sim)
+}
+
+func TestEmbeddingsStemmed(t *testing.T) {
+	e, err := LoadEmbeddings(strings.NewReader("detect 1 0\ndetected 0 1\ncat 1 1\nx-ray 1 1\n"), TextEmbeddings)
+	require.NoError(t, err)
+
+	se := e.Stemmed()
+	require.Equal(t, 2, se.Len()) // "x-ray" isn't a token.
+	doc, ok := se.DocumentVector(Tokenize("The cat detected it."))
+	require.True(t, ok)
+	require.InDeltaSlice(t, []float32{0.75, 0.75}, doc, 1e-6) // (cat + (detect+detected)/2) / 2.
+}
+
+func TestLoadEmbeddingsFormats(t *testing.T) {
+	words := []string{"cat", "dog"}
+	vectors := [][]float32{{0.5, -1}, {0.25, 2}}
+
+	// word2vec text, with a header.
+	e, err := LoadEmbeddings(strings.NewReader("2 2\ncat 0.5 -1\ndog 0.25 2\n"), TextEmbeddings)
+	require.NoError(t, err)
+	require.Equal(t, 2, e.Len())
+
+	// word2vec binary.
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%d %d\n", len(words), 2)
+	for i, w := range words {
+		buf.WriteString(w + " ")
+		for _, v := range vectors[i] {
+			binary.Write(&buf, binary.LittleEndian, math.Float32bits(v))
+		}
+		buf.WriteByte('\n')
+	}
+	e, err = LoadEmbeddings(&buf, BinaryEmbeddings)
+	require.NoError(t, err)
+	for i, w := range words {
+		vec, ok := e.Vector(w)
+		require.True(t, ok, w)
+		require.Equal(t, vectors[i], vec)
+	}
+
+	for _, data := range []string{"", "cat\n", "cat 1 2\ndog 1\n", "cat 1 x\n", "cat 1\ncat 2\n"} {
+		_, err := LoadEmbeddings(strings.NewReader(data), TextEmbeddings)
+		require.Error(t, err, data)
+	}
+	for _, data := range []string{"", "2\n", "2 2\ncat \x00\x00", "1 2\ncat \x00\x00\x00"} {
+		_, err := LoadEmbeddings(strings.NewReader(data), BinaryEmbeddings)
+		require.Error(t, err, data)
+	}
+}
+
+func ExampleEmbeddings_Analogy() {
+	e, _ := LoadEmbeddings(strings.NewReader(`king 0.9 0.8
+queen 0.9 -0.8
+man 0.1 0.8
+woman 0.1 -0.8
+`), TextEmbeddings)
+
+	neighbors, _ := e.Analogy("king", "man", "woman", 1)
+	fmt.Println(neighbors[0].Word)
+
+	// Output:
+	// queen
+}
@@ -0,0 +1,361 @@
+package nlp
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+/*
+Input encoding.
+Tokenize expects UTF-8 text, but files such as sherlock.txt start with a byte order mark (BOM),
+and text from older systems is often UTF-16 or Latin-1 (Windows-1252 / ISO-8859-1).
+Reader detects the encoding of its input, strips the BOM, and decodes the text to UTF-8.
+*/
+
+// Encoding is a text encoding.
+type Encoding int
+
+const (
+	UTF8 Encoding = iota
+	UTF16LE
+	UTF16BE
+	Windows1252
+	ISO88591
+)
+
+var encodingNames = map[Encoding]string{
+	UTF8:        "UTF-8",
+	UTF16LE:     "UTF-16LE",
+	UTF16BE:     "UTF-16BE",
+	Windows1252: "windows-1252",
+	ISO88591:    "ISO-8859-1",
+}
+
+// String implements fmt.Stringer.
+func (e Encoding) String() string {
+	if name, ok := encodingNames[e]; ok {
+		return name
+	}
+	return fmt.Sprintf("Encoding(%d)", int(e))
+}
+
+// charsets maps charset names (e.g. from a Content-Type header) to encodings.
+var charsets = map[string]Encoding{
+	"utf-8":        UTF8,
+	"utf8":         UTF8,
+	"us-ascii":     UTF8,
+	"ascii":        UTF8,
+	"utf-16le":     UTF16LE,
+	"utf-16be":     UTF16BE,
+	"windows-1252": Windows1252,
+	"cp1252":       Windows1252,
+	"iso-8859-1":   ISO88591,
+	"latin1":       ISO88591,
+	"latin-1":      ISO88591,
+}
+
+// ParseCharset returns the Encoding for a charset name such as "utf-8" or "ISO-8859-1".
+// "utf-16" (without byte order) is returned as UTF16BE, unless the text starts with a BOM that says otherwise.
+func ParseCharset(name string) (Encoding, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "utf-16" {
+		return UTF16BE, nil
+	}
+	if enc, ok := charsets[name]; ok {
+		return enc, nil
+	}
+	return UTF8, fmt.Errorf("unsupported charset: %q", name)
+}
+
+var boms = []struct {
+	enc Encoding
+	bom []byte
+}{
+	{UTF8, []byte{0xEF, 0xBB, 0xBF}},
+	{UTF16LE, []byte{0xFF, 0xFE}},
+	{UTF16BE, []byte{0xFE, 0xFF}},
+}
+
+// DetectEncoding guesses the encoding of data, which is the start of the text.
+// It returns the encoding and the size of the BOM (0 if there is none).
+func DetectEncoding(data []byte) (Encoding, int) {
+	for _, b := range boms {
+		if bytes.HasPrefix(data, b.bom) {
+			return b.enc, len(b.bom)
+		}
+	}
+
+	// Mostly ASCII text in UTF-16 has a zero in every other byte.
+	if len(data) >= 4 {
+		var even, odd int
+		for i := 0; i+1 < len(data); i += 2 {
+			if data[i] == 0 {
+				even++
+			}
+			if data[i+1] == 0 {
+				odd++
+			}
+		}
+		pairs := len(data) / 2
+		switch {
+		case odd > pairs/4 && even*4 < odd:
+			return UTF16LE, 0
+		case even > pairs/4 && odd*4 < even:
+			return UTF16BE, 0
+		}
+	}
+
+	// data might end in the middle of a UTF-8 sequence.
+	for i := 0; i < utf8.UTFMax && i < len(data); i++ {
+		if utf8.Valid(data[:len(data)-i]) {
+			return UTF8, 0
+		}
+	}
+	return Windows1252, 0
+}
+
+// InvalidSequenceError is returned by a strict Reader when it finds bytes that are not valid in its encoding.
+type InvalidSequenceError struct {
+	Encoding Encoding
+	Offset   int64 // Offset in the input, after the BOM.
+}
+
+func (e *InvalidSequenceError) Error() string {
+	return fmt.Sprintf("invalid %s byte sequence at offset %d", e.Encoding, e.Offset)
+}
+
+// Reader decodes text to UTF-8.
+// By default invalid byte sequences are replaced with utf8.RuneError (U+FFFD) and counted, see Invalid.
+type Reader struct {
+	// Strict makes Read return an *InvalidSequenceError on invalid byte sequences.
+	Strict bool
+
+	r       *bufio.Reader
+	enc     Encoding
+	offset  int64  // Offset of the next input byte.
+	pending []byte // Decoded bytes not returned yet.
+	invalid int
+	err     error
+}
+
+// sniffSize is how much of the input NewReader looks at to detect the encoding.
+const sniffSize = 1024
+
+// NewReader returns a Reader that detects the encoding of r.
+func NewReader(r io.Reader) *Reader {
+	br := bufio.NewReader(r)
+	data, _ := br.Peek(sniffSize) // A short read is fine, errors will show again on Read.
+	enc, n := DetectEncoding(data)
+	br.Discard(n)
+	return &Reader{r: br, enc: enc}
+}
+
+// NewReaderEncoding returns a Reader that decodes r from enc.
+// A BOM at the start of r is stripped, and for UTF-16 it decides the byte order.
+func NewReaderEncoding(r io.Reader, enc Encoding) *Reader {
+	br := bufio.NewReader(r)
+	data, _ := br.Peek(3)
+	for _, b := range boms {
+		if !bytes.HasPrefix(data, b.bom) {
+			continue
+		}
+		isUTF16 := enc == UTF16LE || enc == UTF16BE
+		if b.enc == enc || (isUTF16 && b.enc != UTF8) {
+			enc = b.enc
+			br.Discard(len(b.bom))
+		}
+		break
+	}
+	return &Reader{r: br, enc: enc}
+}
+
+// ReadFileUTF8 reads the file at path, detecting its encoding like NewReader, and returns it decoded to UTF-8.
+func ReadFileUTF8(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	var sb strings.Builder
+	if _, err := io.Copy(&sb, NewReader(file)); err != nil {
+		return "", fmt.Errorf("%s: %w", path, err)
+	}
+	return sb.String(), nil
+}
+
+// Encoding returns the encoding r decodes from.
+func (r *Reader) Encoding() Encoding {
+	return r.enc
+}
+
+// Invalid returns the number of invalid byte sequences r replaced so far.
+func (r *Reader) Invalid() int {
+	return r.invalid
+}
+
+// Read implements io.Reader.
+func (r *Reader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		r.err = r.fill()
+	}
+
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+// fill decodes the next chunk of input into r.pending.
+func (r *Reader) fill() error {
+	var err error
+	switch r.enc {
+	case UTF16LE, UTF16BE:
+		err = r.fillUTF16()
+	case Windows1252, ISO88591:
+		err = r.fillLatin()
+	default:
+		err = r.fillUTF8()
+	}
+	return err
+}
+
+func (r *Reader) invalidSequence() error {
+	if r.Strict {
+		return &InvalidSequenceError{r.enc, r.offset}
+	}
+	r.invalid++
+	r.pending = utf8.AppendRune(r.pending, utf8.RuneError)
+	return nil
+}
+
+func (r *Reader) fillUTF8() error {
+	data, err := r.r.Peek(sniffSize)
+	if len(data) == 0 {
+		return err
+	}
+
+	i := 0
+	for i < len(data) {
+		c, size := utf8.DecodeRune(data[i:])
+		if c == utf8.RuneError && size <= 1 {
+			// The sequence might continue in the next chunk.
+			if !utf8.FullRune(data[i:]) && err == nil && i > 0 {
+				break
+			}
+			r.pending = append(r.pending, data[:i]...)
+			r.offset += int64(i)
+			if ierr := r.invalidSequence(); ierr != nil {
+				return ierr
+			}
+			r.r.Discard(i + 1)
+			r.offset++
+			return nil
+		}
+		i += size
+	}
+	r.pending = append(r.pending, data[:i]...)
+	r.r.Discard(i)
+	r.offset += int64(i)
+	return nil
+}
+
+func (r *Reader) fillUTF16() error {
+	data, err := r.r.Peek(sniffSize)
+	if len(data) < 2 {
+		if len(data) == 1 {
+			if ierr := r.invalidSequence(); ierr != nil {
+				return ierr
+			}
+			r.r.Discard(1)
+			r.offset++
+		}
+		if err == nil {
+			err = io.EOF
+		}
+		return err
+	}
+
+	unit := func(i int) rune {
+		if r.enc == UTF16LE {
+			return rune(data[i]) | rune(data[i+1])<<8
+		}
+		return rune(data[i])<<8 | rune(data[i+1])
+	}
+
+	i := 0
+loop:
+	for i+1 < len(data) {
+		c := unit(i)
+		switch {
+		case utf16.IsSurrogate(c) && c < 0xDC00:
+			if i+3 >= len(data) {
+				if err == nil && i > 0 {
+					break loop // The pair continues in the next chunk.
+				}
+			} else if c2 := unit(i + 2); c2 >= 0xDC00 && c2 <= 0xDFFF {
+				r.pending = utf8.AppendRune(r.pending, utf16.DecodeRune(c, c2))
+				i += 4
+				continue
+			}
+			fallthrough
+		case utf16.IsSurrogate(c):
+			r.r.Discard(i)
+			r.offset += int64(i)
+			if ierr := r.invalidSequence(); ierr != nil {
+				return ierr
+			}
+			r.r.Discard(2)
+			r.offset += 2
+			return nil
+		default:
+			r.pending = utf8.AppendRune(r.pending, c)
+			i += 2
+		}
+	}
+	r.r.Discard(i)
+	r.offset += int64(i)
+	return nil
+}
+
+// windows1252 maps the bytes 0x80-0x9F, where Windows-1252 differs from ISO-8859-1. Zero means undefined.
+var windows1252 = [32]rune{
+	'€', 0, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', 0, 'Ž', 0,
+	0, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', 0, 'ž', 'Ÿ',
+}
+
+func (r *Reader) fillLatin() error {
+	data, err := r.r.Peek(sniffSize)
+	if len(data) == 0 {
+		return err
+	}
+
+	for i, b := range data {
+		c := rune(b)
+		if r.enc == Windows1252 && b >= 0x80 && b <= 0x9F {
+			c = windows1252[b-0x80]
+			if c == 0 {
+				r.r.Discard(i)
+				r.offset += int64(i)
+				if ierr := r.invalidSequence(); ierr != nil {
+					return ierr
+				}
+				r.r.Discard(1)
+				r.offset++
+				return nil
+			}
+		}
+		r.pending = utf8.AppendRune(r.pending, c)
+	}
+	r.r.Discard(len(data))
+	r.offset += int64(len(data))
+	return nil
+}
@@ -0,0 +1,129 @@
+package nlp
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/require"
+)
+
+func encodeUTF16(s string, le bool) []byte {
+	var buf []byte
+	for _, u := range utf16.Encode([]rune(s)) {
+		if le {
+			buf = append(buf, byte(u), byte(u>>8))
+		} else {
+			buf = append(buf, byte(u>>8), byte(u))
+		}
+	}
+	return buf
+}
+
+func TestReader(t *testing.T) {
+	text := "Who's on first? Café 😀"
+	var cases = []struct {
+		name string
+		data []byte
+		enc  Encoding
+		text string
+	}{
+		{"utf8", []byte(text), UTF8, text},
+		{"utf8 bom", append([]byte{0xEF, 0xBB, 0xBF}, text...), UTF8, text},
+		{"utf16le bom", append([]byte{0xFF, 0xFE}, encodeUTF16(text, true)...), UTF16LE, text},
+		{"utf16be bom", append([]byte{0xFE, 0xFF}, encodeUTF16(text, false)...), UTF16BE, text},
+		{"utf16le", encodeUTF16(text, true), UTF16LE, text},
+		{"utf16be", encodeUTF16(text, false), UTF16BE, text},
+		{"windows-1252", []byte("caf\xe9 \x93quoted\x94"), Windows1252, "café “quoted”"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := NewReader(bytes.NewReader(tc.data))
+			require.Equal(t, tc.enc, r.Encoding())
+			data, err := io.ReadAll(r)
+			require.NoError(t, err)
+			require.Equal(t, tc.text, string(data))
+			require.Equal(t, 0, r.Invalid())
+		})
+	}
+}
+
+func TestReaderChunks(t *testing.T) {
+	// Multi-byte runes and surrogate pairs that span chunk boundaries.
+	text := strings.Repeat("aé😀", 1000)
+	inputs := map[Encoding][]byte{
+		UTF8:    []byte(text),
+		UTF16LE: encodeUTF16(text, true),
+		UTF16BE: encodeUTF16(text, false),
+	}
+	for enc, data := range inputs {
+		r := NewReaderEncoding(bytes.NewReader(data), enc)
+		out, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.Equal(t, text, string(out))
+	}
+}
+
+func TestReaderEncoding(t *testing.T) {
+	r := NewReaderEncoding(strings.NewReader("caf\xe9"), ISO88591)
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, "café", string(data))
+
+	// The BOM decides the byte order of UTF-16.
+	r = NewReaderEncoding(bytes.NewReader(append([]byte{0xFF, 0xFE}, encodeUTF16("hi", true)...)), UTF16BE)
+	data, err = io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, "hi", string(data))
+}
+
+func TestReaderInvalid(t *testing.T) {
+	r := NewReaderEncoding(strings.NewReader("a\xffb\xfe"), UTF8)
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, "a�b�", string(data))
+	require.Equal(t, 2, r.Invalid())
+
+	r = NewReaderEncoding(strings.NewReader("ab\xffc"), UTF8)
+	r.Strict = true
+	data, err = io.ReadAll(r)
+	var ierr *InvalidSequenceError
+	require.True(t, errors.As(err, &ierr))
+	require.Equal(t, int64(2), ierr.Offset)
+	require.Equal(t, "ab", string(data))
+}
+
+func TestReaderSherlock(t *testing.T) {
+	file, err := os.Open("testdata/sherlock.txt")
+	require.NoError(t, err)
+	defer file.Close()
+
+	r := NewReader(file)
+	require.Equal(t, UTF8, r.Encoding())
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(data), "\r\nProject Gutenberg"))
+}
+
+func TestReadFileUTF8(t *testing.T) {
+	text, err := ReadFileUTF8("testdata/sherlock.txt")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(text, "\r\nProject Gutenberg")) // Without the BOM.
+
+	_, err = ReadFileUTF8("testdata/missing.txt")
+	require.Error(t, err)
+}
+
+func TestParseCharset(t *testing.T) {
+	enc, err := ParseCharset("ISO-8859-1")
+	require.NoError(t, err)
+	require.Equal(t, ISO88591, enc)
+
+	_, err = ParseCharset("ebcdic")
+	require.Error(t, err)
+}
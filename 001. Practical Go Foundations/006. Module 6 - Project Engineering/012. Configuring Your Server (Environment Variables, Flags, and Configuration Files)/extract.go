@@ -0,0 +1,412 @@
+package nlp
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"nlp/stemmer"
+)
+
+/*
+Typed extractors.
+wordRe only keeps runs of ASCII letters, so things like "sherlock@baker.st", "https://example.com", "$1,200" or "1 March 1891"
+are either lost or split into meaningless pieces.
+The extractors below recognize these spans before word tokenization, and return them as typed tokens with a normalized value.
+*/
+
+// Kind is the kind of a Token.
+type Kind int
+
+const (
+	Word Kind = iota
+	Email
+	URL
+	Phone
+	Number
+	Date
+	Money
+	CreditCard
+	IPAddress
+	Name
+	Hashtag
+	Mention
+	Cashtag
+	Emoticon
+	Emoji
+)
+
+var kindNames = map[Kind]string{
+	Word:       "word",
+	Email:      "email",
+	URL:        "url",
+	Phone:      "phone",
+	Number:     "number",
+	Date:       "date",
+	Money:      "money",
+	CreditCard: "credit_card",
+	IPAddress:  "ip_address",
+	Name:       "name",
+	Hashtag:    "hashtag",
+	Mention:    "mention",
+	Cashtag:    "cashtag",
+	Emoticon:   "emoticon",
+	Emoji:      "emoji",
+}
+
+// String implements fmt.Stringer.
+func (k Kind) String() string {
+	if name, ok := kindNames[k]; ok {
+		return name
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// MarshalText implements encoding.TextMarshaler, so kinds are encoded as names in JSON.
+func (k Kind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (k *Kind) UnmarshalText(data []byte) error {
+	for kind, name := range kindNames {
+		if name == string(data) {
+			*k = kind
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown kind: %q", data)
+}
+
+// Token is a typed span of text.
+type Token struct {
+	Kind  Kind   `json:"kind"`
+	Text  string `json:"text"`  // Original text.
+	Value string `json:"value"` // Normalized value, e.g. "1891-03-01" for "1 March 1891".
+	Start int    `json:"start"` // Byte offset of Text in the input.
+	End   int    `json:"end"`
+}
+
+// Extractor finds spans of a single Kind in text.
+type Extractor struct {
+	Kind    Kind
+	Pattern *regexp.Regexp
+	// Find, if set, is used instead of Pattern to find the [start, end] offsets of the matches, in order.
+	Find func(text string) [][]int
+	// WholeWords rejects the matches that start or end in the middle of a word.
+	// Unlike \b and \B in Pattern, it knows about non-ASCII letters: "@Jos" isn't a match in "@José".
+	WholeWords bool
+	// Normalize returns the normalized value of a match, or false to reject the match.
+	Normalize func(match string) (string, bool)
+	// Prefixes, if set, returns shorter prefixes of a rejected match to try instead, longest first.
+	Prefixes func(match string) []string
+}
+
+// Extract returns the tokens e finds in text, in order of appearance.
+func (e *Extractor) Extract(text string) []Token {
+	find := e.Find
+	if find == nil {
+		find = func(text string) [][]int { return e.Pattern.FindAllStringIndex(text, -1) }
+	}
+
+	var tokens []Token
+	for _, loc := range find(text) {
+		start, end := loc[0], loc[1]
+		// A sign right after a letter or digit is a hyphen (e.g. "10-12"), not part of the match.
+		if start > 0 && (text[start] == '-' || text[start] == '+') && isAlnum(text[start-1]) {
+			start++
+		}
+		if start == end || (e.WholeWords && !isWordBoundary(text, start, end)) {
+			continue
+		}
+		match := text[start:end]
+		value := match
+		if e.Normalize != nil {
+			v, ok := e.Normalize(match)
+			if !ok {
+				if match, v, ok = e.shorter(match); !ok {
+					continue
+				}
+				end = start + len(match)
+			}
+			value = v
+		}
+		tokens = append(tokens, Token{Kind: e.Kind, Text: match, Value: value, Start: start, End: end})
+	}
+	return tokens
+}
+
+// shorter returns the longest prefix of a rejected match that Normalize accepts.
+func (e *Extractor) shorter(match string) (string, string, bool) {
+	if e.Prefixes == nil {
+		return "", "", false
+	}
+	for _, p := range e.Prefixes(match) {
+		if v, ok := e.Normalize(p); ok {
+			return p, v, true
+		}
+	}
+	return "", "", false
+}
+
+const (
+	numPattern   = `(?:\d{1,3}(?:,\d{3})+|\d+)(?:\.\d+)?`
+	monthPattern = `(?:jan(?:uary)?|feb(?:ruary)?|mar(?:ch)?|apr(?:il)?|may|june?|july?|aug(?:ust)?|sep(?:t(?:ember)?)?|oct(?:ober)?|nov(?:ember)?|dec(?:ember)?)`
+)
+
+var (
+	// EmailExtractor finds e-mail addresses, e.g. "Sherlock@Baker.st" -> "sherlock@baker.st".
+	EmailExtractor = &Extractor{
+		Kind:      Email,
+		Pattern:   regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}\b`),
+		Normalize: func(s string) (string, bool) { return strings.ToLower(s), true },
+	}
+
+	// URLExtractor finds http(s) and "www." URLs, e.g. "HTTPS://Example.com/a" -> "https://example.com/a".
+	URLExtractor = &Extractor{
+		Kind:      URL,
+		Pattern:   regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s<>"']*[^\s<>"'.,;:!?)\]]`),
+		Normalize: normalizeURL,
+	}
+
+	// PhoneExtractor finds phone numbers, e.g. "+1 (555) 123-4567" -> "+15551234567".
+	// Without separators, it takes 10 or 11 digits ("5551234567"), or 8 to 15 after a "+" ("+445551234567").
+	PhoneExtractor = &Extractor{
+		Kind:      Phone,
+		Pattern:   regexp.MustCompile(`(?:\+\d{1,3}[\s.-]?)?(?:\(\d{2,4}\)\s?|\b\d{2,4}[\s.-])\d{3,4}[\s.-]\d{4}\b|\+\d{8,15}\b|\b\d{10,11}\b`),
+		Normalize: normalizePhone,
+	}
+
+	// NumberExtractor finds numbers, e.g. "1,234.50" -> "1234.5".
+	NumberExtractor = &Extractor{
+		Kind:      Number,
+		Pattern:   regexp.MustCompile(`[-+]?\b` + numPattern + `\b`),
+		Normalize: normalizeNumber,
+	}
+
+	// DateExtractor finds dates, e.g. "March 1, 1891" or "1st of March 1891" -> "1891-03-01".
+	DateExtractor = &Extractor{
+		Kind: Date,
+		Pattern: regexp.MustCompile(`(?i)\b(?:\d{4}-\d{1,2}-\d{1,2}|\d{1,2}/\d{1,2}/\d{4}|` +
+			`\d{1,2}(?:st|nd|rd|th)?\s+(?:of\s+)?` + monthPattern + `\.?,?\s+\d{4}|` +
+			monthPattern + `\.?\s+\d{1,2}(?:st|nd|rd|th)?,?\s+\d{4})\b`),
+		Normalize: normalizeDate,
+	}
+
+	// MoneyExtractor finds amounts of money, e.g. "$1.5 million" -> "USD 1500000.00".
+	MoneyExtractor = &Extractor{
+		Kind: Money,
+		Pattern: regexp.MustCompile(`(?i)(?:[$€£¥]\s?` + numPattern + `(?:\s?(?:million|billion|bn|k)\b)?|` +
+			`\b` + numPattern + `\s?(?:usd|eur|gbp|jpy|dollars?|euros?|pounds?)\b)`),
+		Normalize: normalizeMoney,
+	}
+
+	// Extractors are the default extractors, in order of priority.
+	Extractors = []*Extractor{
+		EmailExtractor,
+		URLExtractor,
+		DateExtractor,
+		MoneyExtractor,
+		PhoneExtractor,
+		NumberExtractor,
+	}
+)
+
+// Extract returns the non-overlapping spans found by extractors (Extractors if none are given), in order of appearance.
+// When spans overlap, the one that starts first wins, then the longest, then the one from the earlier extractor.
+func Extract(text string, extractors ...*Extractor) []Token {
+	if len(extractors) == 0 {
+		extractors = Extractors
+	}
+
+	type candidate struct {
+		tok      Token
+		priority int
+	}
+	var cands []candidate
+	for i, e := range extractors {
+		for _, tok := range e.Extract(text) {
+			cands = append(cands, candidate{tok, i})
+		}
+	}
+	sort.SliceStable(cands, func(i, j int) bool {
+		ci, cj := cands[i], cands[j]
+		if ci.tok.Start != cj.tok.Start {
+			return ci.tok.Start < cj.tok.Start
+		}
+		if li, lj := ci.tok.End-ci.tok.Start, cj.tok.End-cj.tok.Start; li != lj {
+			return li > lj
+		}
+		return ci.priority < cj.priority
+	})
+
+	var tokens []Token
+	end := 0
+	for _, c := range cands {
+		if c.tok.Start < end {
+			continue // Overlaps a span we already have.
+		}
+		tokens = append(tokens, c.tok)
+		end = c.tok.End
+	}
+	return tokens
+}
+
+// TokenizeTyped is like Tokenize, but keeps the spans found by Extract as typed tokens.
+// Text between these spans is split into Word tokens, whose Value is the lower case, stemmed word.
+func TokenizeTyped(text string) []Token {
+	var tokens []Token
+	pos := 0
+	for _, tok := range Extract(text) {
+		tokens = appendWords(tokens, text, pos, tok.Start, wordRe)
+		tokens = append(tokens, tok)
+		pos = tok.End
+	}
+	return appendWords(tokens, text, pos, len(text), wordRe)
+}
+
+// appendWords appends the words that re finds in text[start:end] to tokens, as Word tokens.
+func appendWords(tokens []Token, text string, start, end int, re *regexp.Regexp) []Token {
+	for _, loc := range re.FindAllStringIndex(text[start:end], -1) {
+		w := text[start+loc[0] : start+loc[1]]
+		token := stemmer.Stem(strings.ToLower(w))
+		if token == "" {
+			continue
+		}
+		tokens = append(tokens, Token{Kind: Word, Text: w, Value: token, Start: start + loc[0], End: start + loc[1]})
+	}
+	return tokens
+}
+
+func isAlnum(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+func normalizeURL(s string) (string, bool) {
+	if strings.HasPrefix(strings.ToLower(s), "www.") {
+		s = "http://" + s
+	}
+	u, err := url.Parse(s)
+	if err != nil || u.Host == "" {
+		return "", false
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	return u.String(), true
+}
+
+func normalizePhone(s string) (string, bool) {
+	var b strings.Builder
+	for i, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == '+' && i == 0:
+			b.WriteRune(r)
+		}
+	}
+	digits := strings.TrimPrefix(b.String(), "+")
+	if len(digits) < 7 || len(digits) > 15 {
+		return "", false
+	}
+	return b.String(), true
+}
+
+// parseNumber parses numbers such as "1,234.5" and "-3".
+func parseNumber(s string) (float64, error) {
+	return strconv.ParseFloat(strings.ReplaceAll(s, ",", ""), 64)
+}
+
+func normalizeNumber(s string) (string, bool) {
+	v, err := parseNumber(s)
+	if err != nil {
+		return "", false
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64), true
+}
+
+var (
+	numRe   = regexp.MustCompile(numPattern)
+	monthRe = regexp.MustCompile(`(?i)` + monthPattern)
+	dayRe   = regexp.MustCompile(`\b\d{1,2}(?:st|nd|rd|th)?\b`)
+	yearRe  = regexp.MustCompile(`\b\d{4}\b`)
+
+	currencies = map[string]string{
+		"$": "USD", "usd": "USD", "dollar": "USD", "dollars": "USD",
+		"€": "EUR", "eur": "EUR", "euro": "EUR", "euros": "EUR",
+		"£": "GBP", "gbp": "GBP", "pound": "GBP", "pounds": "GBP",
+		"¥": "JPY", "jpy": "JPY",
+	}
+
+	multipliers = map[string]float64{
+		"k":       1e3,
+		"million": 1e6,
+		"billion": 1e9,
+		"bn":      1e9,
+	}
+)
+
+func normalizeDate(s string) (string, bool) {
+	var year, month, day int
+	switch {
+	case strings.Count(s, "-") == 2:
+		if _, err := fmt.Sscanf(s, "%d-%d-%d", &year, &month, &day); err != nil {
+			return "", false
+		}
+	case strings.Count(s, "/") == 2:
+		// Numeric dates are read as month/day/year.
+		if _, err := fmt.Sscanf(s, "%d/%d/%d", &month, &day, &year); err != nil {
+			return "", false
+		}
+	default:
+		m := strings.ToLower(monthRe.FindString(s))
+		month = monthIndex(m)
+		year, _ = strconv.Atoi(yearRe.FindString(s))
+		d := strings.TrimRight(strings.ToLower(dayRe.FindString(s)), "stndrh")
+		day, _ = strconv.Atoi(d)
+	}
+
+	t := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if t.Year() != year || int(t.Month()) != month || t.Day() != day {
+		return "", false // E.g. "February 30, 2024".
+	}
+	return t.Format(time.DateOnly), true
+}
+
+func monthIndex(name string) int {
+	for m := time.January; m <= time.December; m++ {
+		if strings.HasPrefix(strings.ToLower(m.String()), name[:3]) {
+			return int(m)
+		}
+	}
+	return 0
+}
+
+func normalizeMoney(s string) (string, bool) {
+	lower := strings.ToLower(s)
+	loc := numRe.FindStringIndex(lower)
+	if loc == nil {
+		return "", false
+	}
+	v, err := parseNumber(lower[loc[0]:loc[1]])
+	if err != nil {
+		return "", false
+	}
+
+	// Currency comes either before (a symbol) or after (a code or name) the number.
+	currency := currencies[strings.TrimSpace(lower[:loc[0]])]
+	rest := strings.TrimSpace(lower[loc[1]:])
+	if m, ok := multipliers[rest]; ok {
+		v *= m
+	} else if c, ok := currencies[rest]; ok {
+		currency = c
+	}
+	if currency == "" {
+		return "", false
+	}
+	return fmt.Sprintf("%s %.2f", currency, v), true
+}
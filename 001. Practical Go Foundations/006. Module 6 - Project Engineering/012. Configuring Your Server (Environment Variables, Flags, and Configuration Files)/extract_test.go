@@ -0,0 +1,69 @@
+package nlp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtract(t *testing.T) {
+	var cases = []struct {
+		text  string
+		kind  Kind
+		match string
+		value string
+	}{
+		{"Write to Sherlock@Baker.st today", Email, "Sherlock@Baker.st", "sherlock@baker.st"},
+		{"See HTTPS://Example.com/a?b=1.", URL, "HTTPS://Example.com/a?b=1", "https://example.com/a?b=1"},
+		{"Visit www.gutenberg.org", URL, "www.gutenberg.org", "http://www.gutenberg.org"},
+		{"Call +1 (555) 123-4567 now", Phone, "+1 (555) 123-4567", "+15551234567"},
+		{"Call 5551234567 now", Phone, "5551234567", "5551234567"},
+		{"It cost 1,234.50 in total", Number, "1,234.50", "1234.5"},
+		{"It was -3 outside", Number, "-3", "-3"},
+		{"On 1891-03-01 we met", Date, "1891-03-01", "1891-03-01"},
+		{"On March 1, 1891 we met", Date, "March 1, 1891", "1891-03-01"},
+		{"On the 1st of March 1891 we met", Date, "1st of March 1891", "1891-03-01"},
+		{"On 03/01/1891 we met", Date, "03/01/1891", "1891-03-01"},
+		{"The fee was $1,200 exactly", Money, "$1,200", "USD 1200.00"},
+		{"A loss of £1.5 million", Money, "£1.5 million", "GBP 1500000.00"},
+		{"It costs 20 euros", Money, "20 euros", "EUR 20.00"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.text, func(t *testing.T) {
+			tokens := Extract(tc.text)
+			require.Len(t, tokens, 1)
+			tok := tokens[0]
+			require.Equal(t, tc.kind, tok.Kind)
+			require.Equal(t, tc.match, tok.Text)
+			require.Equal(t, tc.value, tok.Value)
+			require.Equal(t, tc.match, tc.text[tok.Start:tok.End])
+		})
+	}
+}
+
+func TestExtractInvalidDate(t *testing.T) {
+	tokens := Extract("February 30, 2024")
+	for _, tok := range tokens {
+		require.NotEqual(t, Date, tok.Kind)
+	}
+}
+
+func TestExtractRange(t *testing.T) {
+	tokens := Extract("pages 10-12")
+	require.Len(t, tokens, 2)
+	require.Equal(t, "10", tokens[0].Value)
+	require.Equal(t, "12", tokens[1].Value)
+}
+
+func TestTokenizeTyped(t *testing.T) {
+	tokens := TokenizeTyped("Holmes paid $5 on 1891-03-01")
+	var kinds []Kind
+	var values []string
+	for _, tok := range tokens {
+		kinds = append(kinds, tok.Kind)
+		values = append(values, tok.Value)
+	}
+	require.Equal(t, []Kind{Word, Word, Money, Word, Date}, kinds)
+	require.Equal(t, []string{"holme", "paid", "USD 5.00", "on", "1891-03-01"}, values)
+}
@@ -12,6 +12,7 @@ go 1.25
 require (
 	github.com/BurntSushi/toml v1.5.0
 	github.com/stretchr/testify v1.11.1
+	golang.org/x/text v0.22.0
 )
 
 require (
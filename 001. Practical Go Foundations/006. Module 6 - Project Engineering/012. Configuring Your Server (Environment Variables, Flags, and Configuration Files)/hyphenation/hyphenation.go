@@ -0,0 +1,233 @@
+/*
+Package hyphenation finds where words can be hyphenated, with Frank Liang's algorithm (the one TeX uses).
+
+Patterns are short letter sequences with digits between the letters, e.g. "1tion" or "n2at":
+an odd digit means a word can be hyphenated there, an even digit means it can't.
+All the patterns that match a word are applied, and at every position the highest digit wins.
+E.g., the English patterns hyphenate "concatenation" as "con-cate-na-tion".
+Words the patterns get wrong are listed as exceptions, with their hyphens.
+*/
+package hyphenation
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+//go:embed en-us.tex
+var englishPatterns string
+
+// Hyphenator finds the hyphenation points of words.
+type Hyphenator struct {
+	// LeftMin and RightMin are the minimal number of letters before the first and after the last hyphen.
+	LeftMin  int
+	RightMin int
+
+	patterns   map[string][]int // Letters -> digits, one more than the letters.
+	maxLen     int              // Number of letters in the longest pattern.
+	exceptions map[string][]int // Lower case word -> rune offsets of its hyphens.
+}
+
+var (
+	englishOnce sync.Once
+	english     *Hyphenator
+)
+
+// English returns a Hyphenator with the US English TeX patterns.
+func English() *Hyphenator {
+	englishOnce.Do(func() {
+		h, err := Load(strings.NewReader(englishPatterns))
+		if err != nil {
+			panic(err) // Can't happen, the patterns are embedded.
+		}
+		h.RightMin = 3
+		english = h
+	})
+	h := *english // The patterns are shared, LeftMin and RightMin are not.
+	return &h
+}
+
+/*
+Load reads patterns in TeX format: patterns inside "\patterns{...}" and exceptions (words with hyphens, e.g. "ta-ble")
+inside "\hyphenation{...}". Text after "%" is a comment.
+A file that is only a list of patterns (e.g. the hyph-*.pat.txt files of the hyph-utf8 project) works too.
+LeftMin and RightMin are set to 2.
+*/
+func Load(r io.Reader) (*Hyphenator, error) {
+	h := &Hyphenator{
+		LeftMin:    2,
+		RightMin:   2,
+		patterns:   make(map[string][]int),
+		exceptions: make(map[string][]int),
+	}
+
+	s := bufio.NewScanner(r)
+	lnum := 0
+	inExceptions := false
+	for s.Scan() {
+		lnum++
+		line, _, _ := strings.Cut(s.Text(), "%")
+		for _, field := range strings.Fields(line) {
+			switch {
+			case strings.HasPrefix(field, `\patterns{`):
+				inExceptions = false
+				field = strings.TrimPrefix(field, `\patterns{`)
+			case strings.HasPrefix(field, `\hyphenation{`):
+				inExceptions = true
+				field = strings.TrimPrefix(field, `\hyphenation{`)
+			}
+			field, closed := strings.CutSuffix(field, "}")
+
+			var err error
+			switch {
+			case field == "":
+			case inExceptions:
+				err = h.addException(field)
+			default:
+				err = h.addPattern(field)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%d: %w", lnum, err)
+			}
+			if closed {
+				inExceptions = false
+			}
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if len(h.patterns) == 0 && len(h.exceptions) == 0 {
+		return nil, fmt.Errorf("no patterns")
+	}
+	return h, nil
+}
+
+// addPattern adds a pattern such as "n2at" or ".ach4".
+func (h *Hyphenator) addPattern(pattern string) error {
+	var letters []rune
+	digits := []int{0}
+	for _, r := range pattern {
+		switch {
+		case r >= '0' && r <= '9':
+			if digits[len(digits)-1] != 0 {
+				return fmt.Errorf("bad pattern %q: consecutive digits", pattern)
+			}
+			digits[len(digits)-1] = int(r - '0')
+		case unicode.IsLetter(r) || r == '.' || r == '\'' || r == '’':
+			letters = append(letters, unicode.ToLower(r))
+			digits = append(digits, 0)
+		default:
+			return fmt.Errorf("bad pattern %q: unexpected %q", pattern, r)
+		}
+	}
+	if len(letters) == 0 {
+		return fmt.Errorf("bad pattern %q: no letters", pattern)
+	}
+
+	h.patterns[string(letters)] = digits
+	h.maxLen = max(h.maxLen, len(letters))
+	return nil
+}
+
+// addException adds an exception such as "ta-ble".
+func (h *Hyphenator) addException(word string) error {
+	var letters []rune
+	var points []int
+	for _, r := range word {
+		if r == '-' {
+			points = append(points, len(letters))
+			continue
+		}
+		letters = append(letters, unicode.ToLower(r))
+	}
+	if len(letters) == 0 {
+		return fmt.Errorf("bad exception %q", word)
+	}
+	h.exceptions[string(letters)] = points
+	return nil
+}
+
+// Points returns the byte offsets in word where it can be hyphenated, e.g. [3] for "concat" (con-cat).
+func (h *Hyphenator) Points(word string) []int {
+	runes := []rune(strings.ToLower(word))
+	n := len(runes)
+	if n < h.LeftMin+h.RightMin {
+		return nil
+	}
+
+	runePoints, ok := h.exceptions[string(runes)]
+	if !ok {
+		runePoints = h.match(runes)
+	}
+
+	var points []int
+	for _, p := range runePoints {
+		if p >= h.LeftMin && n-p >= h.RightMin {
+			points = append(points, runeOffset(word, p))
+		}
+	}
+	return points
+}
+
+// match applies the patterns to runes, and returns the rune offsets of the hyphens.
+func (h *Hyphenator) match(runes []rune) []int {
+	w := make([]rune, 0, len(runes)+2)
+	w = append(w, '.')
+	w = append(w, runes...)
+	w = append(w, '.')
+
+	// values[i] is the highest digit between w[i-1] and w[i].
+	values := make([]int, len(w)+1)
+	for i := range w {
+		for j := i + 1; j <= min(len(w), i+h.maxLen); j++ {
+			digits, ok := h.patterns[string(w[i:j])]
+			if !ok {
+				continue
+			}
+			for k, d := range digits {
+				values[i+k] = max(values[i+k], d)
+			}
+		}
+	}
+
+	var points []int
+	for i := 1; i < len(runes); i++ {
+		if values[i+1]%2 == 1 { // Between runes[i-1] and runes[i], w has a leading ".".
+			points = append(points, i)
+		}
+	}
+	return points
+}
+
+// runeOffset returns the byte offset of the n-th rune in s.
+func runeOffset(s string, n int) int {
+	offset := 0
+	for range n {
+		_, size := utf8.DecodeRuneInString(s[offset:])
+		offset += size
+	}
+	return offset
+}
+
+// Split returns the parts of word between its hyphenation points, e.g. ["ta" "ble"] for "table".
+func (h *Hyphenator) Split(word string) []string {
+	var parts []string
+	start := 0
+	for _, p := range h.Points(word) {
+		parts = append(parts, word[start:p])
+		start = p
+	}
+	return append(parts, word[start:])
+}
+
+// Hyphenate returns word with sep at its hyphenation points, e.g. Hyphenate("hyphenation", "\u00ad") for soft hyphens.
+func (h *Hyphenator) Hyphenate(word, sep string) string {
+	return strings.Join(h.Split(word), sep)
+}
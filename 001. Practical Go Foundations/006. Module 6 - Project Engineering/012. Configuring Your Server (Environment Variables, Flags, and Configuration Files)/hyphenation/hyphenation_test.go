@@ -0,0 +1,76 @@
+package hyphenation
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnglish(t *testing.T) {
+	var cases = []struct {
+		word     string
+		expected string
+	}{
+		{"hyphenation", "hy-phen-a-tion"}, // Exception.
+		{"computer", "com-puter"},         // At least 3 letters after the last hyphen.
+		{"algorithm", "al-go-rithm"},
+		{"Sherlock", "Sher-lock"},
+		{"detective", "de-tec-tive"},
+		{"extraordinary", "ex-tra-or-di-nary"},
+		{"academy", "acad-emy"}, // Exception "acad-e-my", but RightMin is 3.
+		{"table", "ta-ble"},
+		{"cat", "cat"},
+		{"", ""},
+	}
+
+	h := English()
+	for _, tc := range cases {
+		t.Run(tc.word, func(t *testing.T) {
+			require.Equal(t, tc.expected, h.Hyphenate(tc.word, "-"))
+		})
+	}
+}
+
+func TestMinLetters(t *testing.T) {
+	h := English()
+	require.Equal(t, []string{"ta", "ble"}, h.Split("table"))
+
+	h.RightMin = 4
+	require.Equal(t, []string{"table"}, h.Split("table"))
+	require.Equal(t, 3, English().RightMin) // The change doesn't leak to other hyphenators.
+}
+
+func TestLoad(t *testing.T) {
+	patterns := `
+% Toy German patterns.
+\patterns{
+1ä 1b 1g 1r 1t 1ß
+.st4r   % No break between "s" and "tr".
+}
+\hyphenation{Bä-ren-stra-ße}
+`
+	h, err := Load(strings.NewReader(patterns))
+	require.NoError(t, err)
+	require.Equal(t, "Bä-ren-stra-ße", h.Hyphenate("Bärenstraße", "-"))
+	require.Equal(t, []int{3}, h.Points("Bären")) // Byte offsets, "ä" is 2 bytes.
+	require.Equal(t, "Stra-ße", h.Hyphenate("Straße", "-"))
+
+	_, err = Load(strings.NewReader("a12b"))
+	require.Error(t, err)
+	_, err = Load(strings.NewReader("% nothing"))
+	require.Error(t, err)
+}
+
+func ExampleHyphenator_Hyphenate() {
+	h := English()
+	for _, word := range []string{"hyphenation", "concatenation", "Holmes"} {
+		fmt.Println(h.Hyphenate(word, "-"))
+	}
+
+	// Output:
+	// hy-phen-a-tion
+	// con-cate-na-tion
+	// Holmes
+}
@@ -0,0 +1,113 @@
+package nlp
+
+import (
+	"math"
+	"sort"
+)
+
+/*
+Keyness.
+Keyness finds the words that characterize a corpus (A) compared to a reference corpus (B):
+the words that are used more (or less) often in A than B, relative to the sizes of the corpora.
+For every word, the contingency table is:
+
+	             A              B
+	word         a              b
+	other words  total(A) - a   total(B) - b
+
+	- G² (log-likelihood) and chi-square test if the difference is significant. With one degree of freedom,
+	  a score above 3.84 is significant at p < 0.05, above 6.63 at p < 0.01 and above 10.83 at p < 0.001.
+	- They grow with the size of the corpora, so they don't tell how big the difference is.
+	  The log ratio does: it's the binary log of the ratio of the relative frequencies, 1 means twice as frequent in A.
+*/
+
+// FrequencyTable maps tokens to their counts.
+type FrequencyTable map[string]int
+
+// Add counts the tokens of text, with Tokenize.
+func (ft FrequencyTable) Add(text string) {
+	for _, tok := range Tokenize(text) {
+		ft[tok]++
+	}
+}
+
+// Total returns the number of tokens.
+func (ft FrequencyTable) Total() int {
+	total := 0
+	for _, c := range ft {
+		total += c
+	}
+	return total
+}
+
+// Keyword is the keyness of a word in corpus A compared to corpus B.
+type Keyword struct {
+	Word      string  `json:"word"`
+	CountA    int     `json:"count_a"`
+	CountB    int     `json:"count_b"`
+	G2        float64 `json:"g2"`
+	ChiSquare float64 `json:"chi_square"`
+	LogRatio  float64 `json:"log_ratio"` // Positive if the word is overused in A, negative if it's underused.
+	P         float64 `json:"p"`         // P-value of G².
+}
+
+// zeroCount replaces counts of 0 in the log ratio, which would otherwise be infinite.
+const zeroCount = 0.5
+
+// Keyness compares the words of a and b, and returns them by decreasing G².
+// Words that occur less than minCount times in a and b together are skipped.
+func Keyness(a, b FrequencyTable, minCount int) []Keyword {
+	totalA, totalB := float64(a.Total()), float64(b.Total())
+	if totalA == 0 || totalB == 0 {
+		return nil
+	}
+
+	var keywords []Keyword
+	add := func(word string) {
+		ca, cb := a[word], b[word]
+		if ca+cb < max(minCount, 1) {
+			return
+		}
+		oa, ob := float64(ca), float64(cb)
+		g2 := gSquared([4]float64{oa, ob, totalA - oa, totalB - ob})
+		keywords = append(keywords, Keyword{
+			Word:      word,
+			CountA:    ca,
+			CountB:    cb,
+			G2:        g2,
+			ChiSquare: chiSquare(oa, ob, totalA-oa, totalB-ob),
+			LogRatio:  math.Log2((max(oa, zeroCount) / totalA) / (max(ob, zeroCount) / totalB)),
+			P:         ChiSquarePValue(g2),
+		})
+	}
+	for word := range a {
+		add(word)
+	}
+	for word := range b {
+		if _, ok := a[word]; !ok {
+			add(word)
+		}
+	}
+
+	sort.Slice(keywords, func(i, j int) bool {
+		if keywords[i].G2 != keywords[j].G2 {
+			return keywords[i].G2 > keywords[j].G2
+		}
+		return keywords[i].Word < keywords[j].Word
+	})
+	return keywords
+}
+
+// chiSquare returns Pearson's chi-square of the 2x2 contingency table [[a b] [c d]].
+func chiSquare(a, b, c, d float64) float64 {
+	den := (a + b) * (c + d) * (a + c) * (b + d)
+	if den == 0 {
+		return 0
+	}
+	return (a + b + c + d) * (a*d - b*c) * (a*d - b*c) / den
+}
+
+// ChiSquarePValue returns the p-value of a chi-square (or G²) score with one degree of freedom.
+func ChiSquarePValue(score float64) float64 {
+	return math.Erfc(math.Sqrt(score / 2))
+}
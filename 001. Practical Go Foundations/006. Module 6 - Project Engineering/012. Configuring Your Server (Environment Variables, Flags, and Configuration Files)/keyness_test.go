@@ -0,0 +1,61 @@
+package nlp
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyness(t *testing.T) {
+	a := FrequencyTable{"ship": 10, "the": 90}
+	b := FrequencyTable{"ship": 1, "the": 99}
+
+	keywords := Keyness(a, b, 0)
+	require.Len(t, keywords, 2)
+	kw := keywords[0] // Both have the same G², "ship" sorts first.
+	require.Equal(t, "ship", kw.Word)
+	require.Equal(t, 10, kw.CountA)
+	require.Equal(t, 1, kw.CountB)
+	require.InDelta(t, 8.976, kw.G2, 0.001)
+	require.InDelta(t, 7.792, kw.ChiSquare, 0.001)
+	require.InDelta(t, 3.322, kw.LogRatio, 0.001)
+	require.InDelta(t, 0.0027, kw.P, 0.0001)
+	require.Less(t, keywords[1].LogRatio, 0.0) // "the" is underused in a.
+
+	require.Empty(t, Keyness(a, b, 190)) // "the" occurs 189 times.
+	require.Nil(t, Keyness(a, FrequencyTable{}, 0))
+
+	// A word that's only in b.
+	b["anchor"] = 5
+	for _, kw := range Keyness(a, b, 0) {
+		if kw.Word == "anchor" {
+			require.Equal(t, 0, kw.CountA)
+			require.Less(t, kw.LogRatio, -3.0)
+			return
+		}
+	}
+	t.Fatal("anchor not found")
+}
+
+func TestKeynessSherlock(t *testing.T) {
+	data, err := os.ReadFile("testdata/sherlock.txt")
+	require.NoError(t, err)
+	sentences := SplitSentences(string(data))
+
+	// The first half of the book compared to the second half.
+	a, b := FrequencyTable{}, FrequencyTable{}
+	for i, s := range sentences {
+		if i < len(sentences)/2 {
+			a.Add(s)
+		} else {
+			b.Add(s)
+		}
+	}
+	require.Equal(t, a.Total()+b.Total(), len(Tokenize(string(data))))
+
+	keywords := Keyness(a, b, 10)
+	require.NotEmpty(t, keywords)
+	require.Less(t, keywords[0].P, 0.001)
+	require.Greater(t, keywords[len(keywords)-1].P, 0.05)
+}
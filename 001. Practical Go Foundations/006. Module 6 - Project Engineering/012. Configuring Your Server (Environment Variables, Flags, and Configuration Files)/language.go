@@ -0,0 +1,284 @@
+package nlp
+
+import (
+	"embed"
+	"math"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"nlp/stemmer"
+)
+
+/*
+Language identification.
+Every language has a typical set of character sequences, e.g. "the" and "ing" in English, "sch" and "ich" in German,
+and of letters, e.g. "ø" in Danish and Norwegian, "ã" in Portuguese.
+DetectLanguage compares the character n-grams (1 to 3 letter sequences) of the text with the n-gram profile of every language,
+built from the sample texts in the langdata directory, and ranks the languages by how likely the text is in each.
+*/
+
+//go:embed langdata/*.txt
+var langData embed.FS
+
+// LanguageNames maps the language codes DetectLanguage returns to their English name.
+var LanguageNames = map[string]string{
+	"cs": "Czech",
+	"da": "Danish",
+	"de": "German",
+	"el": "Greek",
+	"en": "English",
+	"es": "Spanish",
+	"fi": "Finnish",
+	"fr": "French",
+	"hu": "Hungarian",
+	"id": "Indonesian",
+	"it": "Italian",
+	"nb": "Norwegian",
+	"nl": "Dutch",
+	"pl": "Polish",
+	"pt": "Portuguese",
+	"ro": "Romanian",
+	"ru": "Russian",
+	"sk": "Slovak",
+	"sv": "Swedish",
+	"tr": "Turkish",
+	"uk": "Ukrainian",
+	"vi": "Vietnamese",
+}
+
+// LanguageGuess is a language and how confident DetectLanguage is that the text is in it.
+type LanguageGuess struct {
+	Lang       string  `json:"lang"`
+	Name       string  `json:"name"`
+	Confidence float64 `json:"confidence"` // Between 0 and 1, the confidences of all guesses add up to 1.
+}
+
+type langProfile struct {
+	lang    string
+	script  *unicode.RangeTable
+	logProb map[string]float64 // N-gram -> log probability.
+	unseen  [4]float64         // Log probability of an n-gram that is not in the profile, by n.
+}
+
+const (
+	// maxNgrams limits how much of the text DetectLanguage looks at.
+	maxNgrams = 3000
+	// smoothing is added to every n-gram count, so unseen n-grams don't have a zero probability.
+	smoothing = 0.1
+	// ngramOverlap tempers the log likelihoods: every letter is in up to 3 n-grams of each length,
+	// so the n-grams are far from independent, and adding their log probabilities overstates the evidence.
+	ngramOverlap = 6.0
+	// priorNgrams is how many n-grams of evidence weigh as much as the uniform prior.
+	// A text with that many n-grams (two short words) gets confidences half way between the model and uniform.
+	priorNgrams = 40.0
+	// minConfidence is the confidence below which TokenizeAuto doesn't trust the guess.
+	minConfidence = 0.5
+)
+
+// ngramSpace is the estimated number of possible n-grams in a language, by n.
+var ngramSpace = [4]float64{0, 100, 2000, 20000}
+
+const (
+	// maxN is the length of the longest n-grams.
+	maxN = 3
+)
+
+var (
+	profilesOnce sync.Once
+	profiles     []*langProfile
+
+	scripts = []*unicode.RangeTable{unicode.Latin, unicode.Cyrillic, unicode.Greek}
+)
+
+// loadProfiles builds the language profiles from the embedded sample texts.
+func loadProfiles() {
+	entries, err := langData.ReadDir("langdata")
+	if err != nil {
+		panic(err) // Can't happen, the files are embedded.
+	}
+
+	for _, e := range entries {
+		data, err := langData.ReadFile(path.Join("langdata", e.Name()))
+		if err != nil {
+			panic(err)
+		}
+
+		text := string(data)
+		counts := make(map[string]int)
+		var totals [maxN + 1]int
+		for _, g := range ngrams(text, -1) {
+			counts[g]++
+			totals[ngramLen(g)]++
+		}
+
+		var denoms [maxN + 1]float64
+		p := &langProfile{
+			lang:    strings.TrimSuffix(e.Name(), ".txt"),
+			script:  dominantScript(text),
+			logProb: make(map[string]float64, len(counts)),
+		}
+		for n := 1; n <= maxN; n++ {
+			denoms[n] = float64(totals[n]) + smoothing*ngramSpace[n]
+			p.unseen[n] = math.Log(smoothing / denoms[n])
+		}
+		for g, c := range counts {
+			p.logProb[g] = math.Log((float64(c) + smoothing) / denoms[ngramLen(g)])
+		}
+		profiles = append(profiles, p)
+	}
+}
+
+// Languages returns the codes of the languages DetectLanguage knows, sorted.
+func Languages() []string {
+	profilesOnce.Do(loadProfiles)
+	langs := make([]string, 0, len(profiles))
+	for _, p := range profiles {
+		langs = append(langs, p.lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// DetectLanguage returns the languages text might be in, most likely first.
+// It returns nil if text has no letters, or is written in a script (e.g. Chinese or Arabic) it has no languages for.
+// Confidences are tempered by how much text there is: a word or two gives guesses close to uniform,
+// a sentence is usually enough for a confident guess.
+func DetectLanguage(text string) []LanguageGuess {
+	profilesOnce.Do(loadProfiles)
+
+	grams := ngrams(text, maxNgrams)
+	if len(grams) == 0 {
+		return nil
+	}
+
+	// Only compare with languages written in the same script as the text.
+	script := dominantScript(text)
+	type score struct {
+		lang string
+		logP float64
+	}
+	var scores []score
+	for _, p := range profiles {
+		if p.script != script {
+			continue
+		}
+		logP := 0.0
+		for _, g := range grams {
+			if lp, ok := p.logProb[g]; ok {
+				logP += lp
+			} else {
+				logP += p.unseen[ngramLen(g)]
+			}
+		}
+		scores = append(scores, score{p.lang, logP})
+	}
+	if len(scores) == 0 {
+		return nil // A script we don't have a language for.
+	}
+
+	// Convert the log likelihoods to probabilities (softmax), all languages being equally likely up front.
+	best := math.Inf(-1)
+	for _, s := range scores {
+		best = max(best, s.logP)
+	}
+	sum := 0.0
+	guesses := make([]LanguageGuess, len(scores))
+	for i, s := range scores {
+		p := math.Exp((s.logP - best) / ngramOverlap)
+		guesses[i] = LanguageGuess{Lang: s.lang, Name: LanguageNames[s.lang], Confidence: p}
+		sum += p
+	}
+	// The profiles are built from a few paragraphs, a handful of n-grams can't be trusted:
+	// shrink the probabilities towards uniform, less so the more n-grams the text has.
+	weight := float64(len(grams)) / (float64(len(grams)) + priorNgrams)
+	uniform := 1 / float64(len(guesses))
+	for i := range guesses {
+		guesses[i].Confidence = weight*guesses[i].Confidence/sum + (1-weight)*uniform
+	}
+
+	sort.SliceStable(guesses, func(i, j int) bool {
+		return guesses[i].Confidence > guesses[j].Confidence
+	})
+	return guesses
+}
+
+// ngrams returns up to max (-1 for all) 1 to 3 letter n-grams of the words in text.
+// Words are lower cased and padded with spaces, so "on" gives "o", "n", " o", "on", "n ", " on" and "on ".
+func ngrams(text string, max int) []string {
+	text = Normalizer{CaseFold: true}.Normalize(text)
+
+	var grams []string
+	for _, word := range letterRe.FindAllString(text, -1) {
+		runes := []rune(" " + word + " ")
+		for n := 1; n <= maxN; n++ {
+			for i := 0; i+n <= len(runes); i++ {
+				g := string(runes[i : i+n])
+				if g == " " {
+					continue
+				}
+				if max >= 0 && len(grams) >= max {
+					return grams
+				}
+				grams = append(grams, g)
+			}
+		}
+	}
+	return grams
+}
+
+// ngramLen returns the number of runes in g.
+func ngramLen(g string) int {
+	return utf8.RuneCountInString(g)
+}
+
+// dominantScript returns the script most of the letters in text are written in,
+// or nil if none of them are in a script we have languages for.
+func dominantScript(text string) *unicode.RangeTable {
+	counts := make([]int, len(scripts))
+	for _, r := range text {
+		for i, s := range scripts {
+			if unicode.Is(s, r) {
+				counts[i]++
+				break
+			}
+		}
+	}
+
+	best := 0
+	for i, c := range counts {
+		if c > counts[best] {
+			best = i
+		}
+	}
+	if counts[best] == 0 {
+		return nil
+	}
+	return scripts[best]
+}
+
+// AnalyzerFor returns an Analyzer for lang (e.g. "de"): it normalizes and case folds the text,
+// removes the stop words of lang, and stems the tokens with the stemmer for lang.
+func AnalyzerFor(lang string) Analyzer {
+	return Analyzer{
+		CharFilters: []CharFilter{Normalizer{CaseFold: true}.CharFilter()},
+		Filters: []Filter{
+			StopWordFilter(lang),
+			MapFilter(func(w string) string { return stemmer.StemLanguage(lang, w) }),
+		},
+	}
+}
+
+// TokenizeAuto detects the language of text, and tokenizes it with the Analyzer for that language.
+// It returns the language ("" if it can't tell, e.g. for a single word) and the tokens.
+func TokenizeAuto(text string) (string, []string) {
+	guesses := DetectLanguage(text)
+	if len(guesses) == 0 || guesses[0].Confidence < minConfidence {
+		return "", DefaultAnalyzer.Tokenize(text)
+	}
+	lang := guesses[0].Lang
+	return lang, AnalyzerFor(lang).Tokenize(text)
+}
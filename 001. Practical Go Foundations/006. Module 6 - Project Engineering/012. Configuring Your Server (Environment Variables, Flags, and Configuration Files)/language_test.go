@@ -0,0 +1,103 @@
+package nlp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	var cases = []struct {
+		lang string
+		text string
+	}{
+		{"en", "I think the detective knew exactly where the missing letter was hidden."},
+		{"de", "Ich glaube, der Detektiv wusste genau, wo der verschwundene Brief versteckt war."},
+		{"fr", "Je crois que le détective savait exactement où la lettre disparue était cachée."},
+		{"es", "Creo que el detective sabía exactamente dónde estaba escondida la carta perdida."},
+		{"it", "Credo che il detective sapesse esattamente dove era nascosta la lettera scomparsa."},
+		{"pt", "Acho que o detetive sabia exatamente onde a carta desaparecida estava escondida."},
+		{"nl", "Ik denk dat de detective precies wist waar de verdwenen brief verstopt was."},
+		{"sv", "Jag tror att detektiven visste precis var det försvunna brevet var gömt."},
+		{"da", "Jeg tror, at detektiven vidste præcis, hvor det forsvundne brev var gemt."},
+		{"nb", "Jeg tror at detektiven visste nøyaktig hvor det forsvunne brevet var gjemt."},
+		{"fi", "Luulen, että etsivä tiesi tarkalleen, mihin kadonnut kirje oli piilotettu."},
+		{"pl", "Myślę, że detektyw dokładnie wiedział, gdzie ukryto zaginiony list."},
+		{"cs", "Myslím, že detektiv přesně věděl, kde byl ztracený dopis ukrytý."},
+		{"sk", "Myslím si, že detektív presne vedel, kde bol stratený list ukrytý."},
+		{"hu", "Azt hiszem, a nyomozó pontosan tudta, hol volt elrejtve az eltűnt levél."},
+		{"ro", "Cred că detectivul știa exact unde era ascunsă scrisoarea dispărută."},
+		{"tr", "Bence dedektif kayıp mektubun nerede saklandığını tam olarak biliyordu."},
+		{"id", "Saya pikir detektif itu tahu persis di mana surat yang hilang itu disembunyikan."},
+		{"vi", "Tôi nghĩ thám tử biết chính xác lá thư bị mất được giấu ở đâu."},
+		{"ru", "Я думаю, детектив точно знал, где было спрятано пропавшее письмо."},
+		{"uk", "Я думаю, детектив точно знав, де був захований зниклий лист."},
+		{"el", "Νομίζω ότι ο ντετέκτιβ ήξερε ακριβώς πού ήταν κρυμμένο το χαμένο γράμμα."},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.lang, func(t *testing.T) {
+			guesses := DetectLanguage(tc.text)
+			require.NotEmpty(t, guesses)
+			require.Equal(t, tc.lang, guesses[0].Lang, "%v", guesses[:min(3, len(guesses))])
+		})
+	}
+}
+
+func TestDetectLanguageShort(t *testing.T) {
+	require.Nil(t, DetectLanguage(""))
+	require.Nil(t, DetectLanguage("42 + 7 = 49"))
+
+	// Scripts we have no languages for.
+	require.Nil(t, DetectLanguage("你好世界，今天天气很好"))
+	require.Nil(t, DetectLanguage("مرحبا بالعالم"))
+
+	// A word or two is a weak signal, the guesses are spread out.
+	for _, text := range []string{"ok", "Danke", "Hello", "Rakastan sinua"} {
+		guesses := DetectLanguage(text)
+		require.NotEmpty(t, guesses)
+		require.Less(t, guesses[0].Confidence, 0.5, text)
+	}
+
+	// More text, more confidence.
+	short := DetectLanguage("Guten Morgen")
+	long := DetectLanguage("Guten Morgen, wie geht es dir heute?")
+	require.Equal(t, "de", long[0].Lang)
+	require.Greater(t, long[0].Confidence, short[0].Confidence)
+
+	// Only languages in the same script are considered.
+	guesses := DetectLanguage("привет")
+	require.Len(t, guesses, 2)
+
+	sum := 0.0
+	for _, g := range DetectLanguage("Who's on first?") {
+		sum += g.Confidence
+	}
+	require.InDelta(t, 1.0, sum, 1e-9)
+}
+
+func TestLanguages(t *testing.T) {
+	langs := Languages()
+	require.GreaterOrEqual(t, len(langs), 20)
+	for _, lang := range langs {
+		require.Contains(t, LanguageNames, lang)
+	}
+}
+
+func TestTokenizeAuto(t *testing.T) {
+	lang, tokens := TokenizeAuto("Die Kinder spielten mit den Zeitungen.")
+	require.Equal(t, "de", lang)
+	require.Equal(t, []string{"kind", "spielt", "zeit"}, tokens)
+
+	lang, tokens = TokenizeAuto("The children were playing with the newspapers.")
+	require.Equal(t, "en", lang)
+	require.Equal(t, []string{"children", "play", "newspaper"}, tokens)
+
+	lang, _ = TokenizeAuto("你好世界，今天天气很好")
+	require.Equal(t, "", lang)
+
+	// Too short to tell.
+	lang, tokens = TokenizeAuto("Danke")
+	require.Equal(t, "", lang)
+	require.Equal(t, []string{"danke"}, tokens)
+}
@@ -0,0 +1,239 @@
+package nlp
+
+import (
+	"cmp"
+	"errors"
+	"math/rand/v2"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+/*
+Topic modeling.
+LDA (Latent Dirichlet Allocation) assumes every document is a mix of a few topics, and every topic a mix of words:
+a ticket can be 70% "billing" and 30% "login", and the "billing" topic uses "invoice" and "refund" a lot.
+LDA finds both mixes from the word counts alone, we only choose the number of topics.
+
+Collapsed Gibbs sampling starts by giving every token a random topic, then goes over the tokens again and again,
+picking a new topic for each in proportion to:
+	(tokens of the document in the topic + Alpha) * (tokens of the word in the topic + Beta) / (tokens in the topic + V*Beta)
+After enough iterations, the counts settle, and the topics emerge.
+
+Topics are only as good as the tokens: remove the stop words first, they're in every topic.
+*/
+
+// LDAOptions are the options of TrainLDA.
+type LDAOptions struct {
+	Topics     int     // Number of topics, required.
+	Alpha      float64 // Prior of the topics in a document, 0 means 0.1. Lower means fewer topics per document.
+	Beta       float64 // Prior of the words in a topic, 0 means 0.01. Lower means fewer words per topic.
+	Iterations int     // Number of Gibbs sampling iterations, 0 means 200.
+	Seed       uint64  // Seed of the random number generator.
+	// Workers is the number of goroutines sampling documents, 0 means runtime.GOMAXPROCS(0).
+	// Every worker samples its documents with its own copy of the topic counts, which are merged after every iteration
+	// (approximate distributed LDA). It's not exactly Gibbs sampling, but it converges to the same topics.
+	// The results depend on Seed and Workers: use the same Workers (e.g. 1) to get the same topics.
+	Workers int
+}
+
+// TopicModel is an LDA model.
+type TopicModel struct {
+	topics      int
+	alpha, beta float64
+	vocab       []string // Word id -> word.
+	topicWord   []int    // Topic*len(vocab) + word id -> count.
+	topicTotal  []int    // Topic -> count.
+	docTopic    [][]int  // Document -> topic -> count.
+	docLen      []int
+}
+
+// TopicWord is a word and its probability in a topic.
+type TopicWord struct {
+	Word        string  `json:"word"`
+	Probability float64 `json:"probability"`
+}
+
+// ldaShard is the documents sampled by a worker.
+type ldaShard struct {
+	docs       []int
+	rng        *rand.Rand
+	topicWord  []int // Local copies of the counts.
+	topicTotal []int
+}
+
+// TrainLDA finds topics in docs, e.g. the output of Tokenize for every document.
+func TrainLDA(docs [][]string, opts LDAOptions) (*TopicModel, error) {
+	if opts.Topics < 1 {
+		return nil, errors.New("number of topics must be positive")
+	}
+	if opts.Alpha < 0 || opts.Beta < 0 || opts.Iterations < 0 || opts.Workers < 0 {
+		return nil, errors.New("negative option")
+	}
+
+	tm := &TopicModel{
+		topics:     opts.Topics,
+		alpha:      cmp.Or(opts.Alpha, 0.1),
+		beta:       cmp.Or(opts.Beta, 0.01),
+		topicTotal: make([]int, opts.Topics),
+		docTopic:   make([][]int, len(docs)),
+		docLen:     make([]int, len(docs)),
+	}
+
+	// Word ids, in order of first occurrence.
+	ids := make(map[string]int)
+	words := make([][]int, len(docs))
+	for d, doc := range docs {
+		words[d] = make([]int, len(doc))
+		for i, w := range doc {
+			id, ok := ids[w]
+			if !ok {
+				id = len(tm.vocab)
+				ids[w] = id
+				tm.vocab = append(tm.vocab, w)
+			}
+			words[d][i] = id
+		}
+	}
+	if len(tm.vocab) == 0 {
+		return nil, errors.New("no words in documents")
+	}
+	v := len(tm.vocab)
+	tm.topicWord = make([]int, opts.Topics*v)
+
+	// Random initial topics.
+	rng := rand.New(rand.NewPCG(opts.Seed, 0))
+	z := make([][]int, len(docs))
+	for d := range docs {
+		tm.docTopic[d] = make([]int, opts.Topics)
+		tm.docLen[d] = len(words[d])
+		z[d] = make([]int, len(words[d]))
+		for i, w := range words[d] {
+			k := rng.IntN(opts.Topics)
+			z[d][i] = k
+			tm.docTopic[d][k]++
+			tm.topicWord[k*v+w]++
+			tm.topicTotal[k]++
+		}
+	}
+
+	workers := min(cmp.Or(opts.Workers, runtime.GOMAXPROCS(0)), max(len(docs), 1))
+	shards := make([]*ldaShard, workers)
+	for s := range shards {
+		shards[s] = &ldaShard{
+			rng:        rand.New(rand.NewPCG(opts.Seed, uint64(s)+1)),
+			topicWord:  make([]int, len(tm.topicWord)),
+			topicTotal: make([]int, len(tm.topicTotal)),
+		}
+	}
+	for d := range docs {
+		s := d * workers / len(docs)
+		shards[s].docs = append(shards[s].docs, d)
+	}
+
+	for range cmp.Or(opts.Iterations, 200) {
+		var wg sync.WaitGroup
+		for _, sh := range shards {
+			copy(sh.topicWord, tm.topicWord)
+			copy(sh.topicTotal, tm.topicTotal)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				tm.sample(sh, words, z)
+			}()
+		}
+		wg.Wait()
+		tm.merge(shards)
+	}
+	return tm, nil
+}
+
+// sample picks a new topic for every token of the documents of sh.
+// Documents belong to one shard, so their counts in docTopic and z are only changed by one goroutine.
+func (tm *TopicModel) sample(sh *ldaShard, words, z [][]int) {
+	v := len(tm.vocab)
+	vBeta := float64(v) * tm.beta
+	p := make([]float64, tm.topics)
+	for _, d := range sh.docs {
+		dt := tm.docTopic[d]
+		for i, w := range words[d] {
+			k := z[d][i]
+			dt[k]--
+			sh.topicWord[k*v+w]--
+			sh.topicTotal[k]--
+
+			total := 0.0
+			for t := range p {
+				total += (float64(dt[t]) + tm.alpha) * (float64(sh.topicWord[t*v+w]) + tm.beta) / (float64(sh.topicTotal[t]) + vBeta)
+				p[t] = total
+			}
+			r := sh.rng.Float64() * total
+			k = sort.SearchFloat64s(p, r)
+			k = min(k, tm.topics-1) // In case of rounding errors.
+
+			z[d][i] = k
+			dt[k]++
+			sh.topicWord[k*v+w]++
+			sh.topicTotal[k]++
+		}
+	}
+}
+
+// merge adds the changes of every shard to the global counts.
+func (tm *TopicModel) merge(shards []*ldaShard) {
+	if len(shards) == 1 {
+		copy(tm.topicWord, shards[0].topicWord)
+		copy(tm.topicTotal, shards[0].topicTotal)
+		return
+	}
+
+	topicWord := make([]int, len(tm.topicWord))
+	copy(topicWord, tm.topicWord)
+	topicTotal := make([]int, len(tm.topicTotal))
+	copy(topicTotal, tm.topicTotal)
+	for _, sh := range shards {
+		for i, c := range sh.topicWord {
+			topicWord[i] += c - tm.topicWord[i]
+		}
+		for i, c := range sh.topicTotal {
+			topicTotal[i] += c - tm.topicTotal[i]
+		}
+	}
+	tm.topicWord, tm.topicTotal = topicWord, topicTotal
+}
+
+// Topics returns the number of topics.
+func (tm *TopicModel) Topics() int {
+	return tm.topics
+}
+
+// TopWords returns the n most likely words of topic.
+func (tm *TopicModel) TopWords(topic, n int) []TopicWord {
+	v := len(tm.vocab)
+	counts := tm.topicWord[topic*v : (topic+1)*v]
+	ids := make([]int, v)
+	for i := range ids {
+		ids[i] = i
+	}
+	sort.SliceStable(ids, func(i, j int) bool {
+		return counts[ids[i]] > counts[ids[j]]
+	})
+
+	denom := float64(tm.topicTotal[topic]) + float64(v)*tm.beta
+	words := make([]TopicWord, min(max(n, 0), v))
+	for i := range words {
+		id := ids[i]
+		words[i] = TopicWord{Word: tm.vocab[id], Probability: (float64(counts[id]) + tm.beta) / denom}
+	}
+	return words
+}
+
+// DocumentTopics returns the probability of every topic in the document at index doc, in the documents given to TrainLDA.
+func (tm *TopicModel) DocumentTopics(doc int) []float64 {
+	denom := float64(tm.docLen[doc]) + float64(tm.topics)*tm.alpha
+	mix := make([]float64, tm.topics)
+	for k, c := range tm.docTopic[doc] {
+		mix[k] = (float64(c) + tm.alpha) / denom
+	}
+	return mix
+}
@@ -0,0 +1,107 @@
+package nlp
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// ticketDocs returns n documents, even ones about billing and odd ones about logins.
+func ticketDocs(n int) [][]string {
+	themes := [][]string{
+		{"invoic", "refund", "payment", "charg", "card", "bill"},
+		{"password", "login", "account", "reset", "lock", "email"},
+	}
+	rng := rand.New(rand.NewPCG(7, 7))
+	docs := make([][]string, n)
+	for d := range docs {
+		words := themes[d%2]
+		for range 20 {
+			docs[d] = append(docs[d], words[rng.IntN(len(words))])
+		}
+	}
+	return docs
+}
+
+func TestTrainLDA(t *testing.T) {
+	docs := ticketDocs(40)
+
+	for _, workers := range []int{1, 4} {
+		t.Run(fmt.Sprintf("workers=%d", workers), func(t *testing.T) {
+			opts := LDAOptions{Topics: 2, Iterations: 50, Seed: 1, Workers: workers}
+			tm, err := TrainLDA(docs, opts)
+			require.NoError(t, err)
+			require.Equal(t, 2, tm.Topics())
+
+			// Every topic is one theme.
+			for k := range tm.Topics() {
+				top := tm.TopWords(k, 6)
+				theme := docs[0]
+				if !slices.Contains(theme, top[0].Word) {
+					theme = docs[1]
+				}
+				for _, tw := range top {
+					require.Contains(t, theme, tw.Word)
+					require.Greater(t, tw.Probability, 0.1)
+				}
+			}
+
+			// Documents of the same theme have the same main topic.
+			main := func(d int) int {
+				mix := tm.DocumentTopics(d)
+				require.InDelta(t, 1, mix[0]+mix[1], 1e-9)
+				if mix[0] > mix[1] {
+					return 0
+				}
+				return 1
+			}
+			for d := range docs {
+				require.Equal(t, main(d%2), main(d), d)
+			}
+			require.NotEqual(t, main(0), main(1))
+
+			// Same seed and workers, same model.
+			tm2, err := TrainLDA(docs, opts)
+			require.NoError(t, err)
+			require.Equal(t, tm, tm2)
+		})
+	}
+}
+
+func TestTrainLDAErrors(t *testing.T) {
+	_, err := TrainLDA(ticketDocs(2), LDAOptions{})
+	require.Error(t, err)
+	_, err = TrainLDA(ticketDocs(2), LDAOptions{Topics: 2, Alpha: -1})
+	require.Error(t, err)
+	_, err = TrainLDA([][]string{{}, nil}, LDAOptions{Topics: 2})
+	require.Error(t, err)
+}
+
+func ExampleTrainLDA() {
+	tickets := []string{
+		"Please refund the double payment on my card.",
+		"The refund for the payment on my card is missing.",
+		"Login fails, the password reset email is missing.",
+		"Password reset fails, and login is locked.",
+	}
+	stopWords := StopWordFilter("en")
+	docs := make([][]string, len(tickets))
+	for i, ticket := range tickets {
+		docs[i] = stopWords(Tokenize(ticket))
+	}
+
+	tm, _ := TrainLDA(docs, LDAOptions{Topics: 2, Seed: 1, Workers: 1})
+	for k := range tm.Topics() {
+		fmt.Printf("topic %d:", k)
+		for _, tw := range tm.TopWords(k, 3) {
+			fmt.Printf(" %s", tw.Word)
+		}
+		fmt.Println()
+	}
+	// Output:
+	// topic 0: login fail password
+	// topic 1: refund payment my
+}
@@ -0,0 +1,275 @@
+package nlp
+
+import (
+	"bufio"
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+/*
+Markov chain text generator.
+An n-gram Markov model learns which token follows every sequence of n tokens (the state) in a corpus, and how often.
+Generating text is a walk through the states: pick the next token at random, in proportion to its count, and repeat.
+With order 1 the text is gibberish, with order 2 or 3 it reads like the corpus, until you look closely.
+Tokenize stems and drops punctuation, the generator keeps words and punctuation as they are, so the text looks real.
+*/
+
+// markovTokenRe matches words (with contractions) and punctuation.
+var markovTokenRe = regexp.MustCompile(`[\p{L}\p{N}]+(?:['’][\p{L}]+)*|[^\s\p{L}\p{N}]`)
+
+// markovBoundary marks the start and end of sentences in states.
+const markovBoundary = ""
+
+// Markov is an n-gram Markov model.
+type Markov struct {
+	order int
+	next  map[string]map[string]int // State (tokens joined by markovSep) -> next token -> count.
+}
+
+// markovSep joins the tokens of a state, it can't be in a token.
+const markovSep = "\x00"
+
+// NewMarkov returns an empty model, where the state is order tokens.
+func NewMarkov(order int) (*Markov, error) {
+	if order < 1 {
+		return nil, fmt.Errorf("bad order: %d", order)
+	}
+	return &Markov{order: order, next: make(map[string]map[string]int)}, nil
+}
+
+// Order returns the number of tokens in a state.
+func (m *Markov) Order() int {
+	return m.order
+}
+
+// Add trains the model on text, sentence by sentence.
+func (m *Markov) Add(text string) {
+	for _, sentence := range SplitSentences(text) {
+		tokens := markovTokenRe.FindAllString(sentence, -1)
+		if len(tokens) == 0 {
+			continue
+		}
+		state := m.startState()
+		for _, tok := range append(tokens, markovBoundary) {
+			m.add(state, tok, 1)
+			state = append(state[1:], tok)
+		}
+	}
+}
+
+func (m *Markov) startState() []string {
+	state := make([]string, m.order)
+	for i := range state {
+		state[i] = markovBoundary
+	}
+	return state
+}
+
+func (m *Markov) add(state []string, tok string, count int) {
+	key := strings.Join(state, markovSep)
+	next, ok := m.next[key]
+	if !ok {
+		next = make(map[string]int)
+		m.next[key] = next
+	}
+	next[tok] += count
+}
+
+// GenerateOptions are the options of Markov.Generate.
+type GenerateOptions struct {
+	// Prompt is the start of the text, its last tokens must be a state of the model.
+	// A prompt shorter than the order must start a sentence of the corpus, e.g. "The" for order 2.
+	Prompt string
+	// Sentences is the number of sentences to generate, including the one the prompt starts, 0 means 1.
+	Sentences int
+	// MaxTokens stops the text after that many tokens, 0 means 1000.
+	MaxTokens int
+	// Temperature flattens (above 1) or sharpens (below 1) the distribution of the next token, 0 means 1.
+	// With a low temperature (e.g. 0.1), the most frequent token is almost always picked.
+	Temperature float64
+}
+
+// Generate returns random text. Use a seeded rng, e.g. rand.New(rand.NewPCG(1, 2)), to get the same text every time.
+func (m *Markov) Generate(rng *rand.Rand, opts GenerateOptions) (string, error) {
+	sentences := max(opts.Sentences, 1)
+	maxTokens := cmp.Or(opts.MaxTokens, 1000)
+	temp := cmp.Or(opts.Temperature, 1)
+	if temp < 0 {
+		return "", fmt.Errorf("bad temperature: %v", temp)
+	}
+
+	tokens := markovTokenRe.FindAllString(opts.Prompt, -1)
+	state := m.startState()
+	for _, tok := range tokens {
+		state = append(state[1:], tok)
+	}
+	if _, ok := m.next[strings.Join(state, markovSep)]; !ok {
+		return "", errors.New("prompt not in model")
+	}
+
+	var out []string
+	for len(out) < maxTokens {
+		tok := m.pick(rng, state, temp)
+		if tok == markovBoundary {
+			sentences--
+			if sentences == 0 {
+				break
+			}
+			state = m.startState()
+			continue
+		}
+		out = append(out, tok)
+		state = append(state[1:], tok)
+	}
+	return joinTokens(append(tokens, out...)), nil
+}
+
+// pick returns a random next token for state, markovBoundary if there's none.
+func (m *Markov) pick(rng *rand.Rand, state []string, temp float64) string {
+	next := m.next[strings.Join(state, markovSep)]
+	if len(next) == 0 {
+		return markovBoundary
+	}
+
+	// Sort the candidates, map order is random and we want the same text for the same seed.
+	candidates := make([]string, 0, len(next))
+	maxCount := 0
+	for tok, count := range next {
+		candidates = append(candidates, tok)
+		maxCount = max(maxCount, count)
+	}
+	sort.Strings(candidates)
+
+	// count^(1/temp), computed relative to the highest count so it doesn't overflow.
+	weights := make([]float64, len(candidates))
+	total := 0.0
+	for i, tok := range candidates {
+		weights[i] = math.Exp((math.Log(float64(next[tok])) - math.Log(float64(maxCount))) / temp)
+		total += weights[i]
+	}
+
+	r := rng.Float64() * total
+	for i, w := range weights {
+		r -= w
+		if r < 0 {
+			return candidates[i]
+		}
+	}
+	return candidates[len(candidates)-1]
+}
+
+// joinTokens joins tokens with spaces, except before closing punctuation and after opening brackets.
+func joinTokens(tokens []string) string {
+	var sb strings.Builder
+	for i, tok := range tokens {
+		if i > 0 && !strings.Contains(".,;:!?)]}", tok) && !strings.Contains("([{", tokens[i-1]) {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(tok)
+	}
+	return sb.String()
+}
+
+/*
+Save writes the model in a text format: the order on the first line, then one line per transition,
+with the state and the next token quoted, and the count:
+
+	order 2
+	"" "" "The" 12
+	"" "The" "door" 3
+
+An empty token is the start or the end of a sentence.
+*/
+func (m *Markov) Save(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "order %d\n", m.order)
+
+	keys := make([]string, 0, len(m.next))
+	for key := range m.next {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		var state strings.Builder
+		for _, tok := range strings.Split(key, markovSep) {
+			state.WriteString(strconv.Quote(tok))
+			state.WriteByte(' ')
+		}
+
+		next := m.next[key]
+		toks := make([]string, 0, len(next))
+		for tok := range next {
+			toks = append(toks, tok)
+		}
+		sort.Strings(toks)
+		for _, tok := range toks {
+			fmt.Fprintf(bw, "%s%s %d\n", state.String(), strconv.Quote(tok), next[tok])
+		}
+	}
+	return bw.Flush()
+}
+
+// LoadMarkov reads a model written by Save.
+func LoadMarkov(r io.Reader) (*Markov, error) {
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1<<20)
+	if !s.Scan() {
+		if err := s.Err(); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("empty model")
+	}
+	var order int
+	if _, err := fmt.Sscanf(s.Text(), "order %d", &order); err != nil {
+		return nil, fmt.Errorf("1: bad header %q", s.Text())
+	}
+	m, err := NewMarkov(order)
+	if err != nil {
+		return nil, fmt.Errorf("1: %w", err)
+	}
+
+	lnum := 1
+	for s.Scan() {
+		lnum++
+		toks, count, err := parseTransition(s.Text(), order+1)
+		if err != nil {
+			return nil, fmt.Errorf("%d: %w", lnum, err)
+		}
+		m.add(toks[:order], toks[order], count)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// parseTransition parses n quoted tokens and a count.
+func parseTransition(line string, n int) ([]string, int, error) {
+	toks := make([]string, n)
+	rest := line
+	for i := range toks {
+		quoted, err := strconv.QuotedPrefix(rest)
+		if err != nil {
+			return nil, 0, fmt.Errorf("bad transition %q", line)
+		}
+		toks[i], _ = strconv.Unquote(quoted)
+		if strings.Contains(toks[i], markovSep) || !utf8.ValidString(toks[i]) {
+			return nil, 0, fmt.Errorf("bad token %q", toks[i])
+		}
+		rest = strings.TrimPrefix(rest[len(quoted):], " ")
+	}
+	count, err := strconv.Atoi(rest)
+	if err != nil || count < 1 {
+		return nil, 0, fmt.Errorf("bad count in %q", line)
+	}
+	return toks, count, nil
+}
@@ -0,0 +1,88 @@
+package nlp
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarkov(t *testing.T) {
+	m, err := NewMarkov(1)
+	require.NoError(t, err)
+	m.Add("The cat sat. The cat ran.")
+
+	rng := rand.New(rand.NewPCG(1, 2))
+	text, err := m.Generate(rng, GenerateOptions{Prompt: "cat", Temperature: 0.01})
+	require.NoError(t, err)
+	require.Contains(t, []string{"cat sat.", "cat ran."}, text)
+
+	// Every walk through this model is a sentence of the corpus.
+	text, err = m.Generate(rng, GenerateOptions{Sentences: 3})
+	require.NoError(t, err)
+	require.Len(t, SplitSentences(text), 3)
+	for _, s := range SplitSentences(text) {
+		require.Contains(t, []string{"The cat sat.", "The cat ran."}, s)
+	}
+
+	_, err = m.Generate(rng, GenerateOptions{Prompt: "dog"})
+	require.Error(t, err)
+	_, err = m.Generate(rng, GenerateOptions{Temperature: -1})
+	require.Error(t, err)
+	_, err = NewMarkov(0)
+	require.Error(t, err)
+}
+
+func TestMarkovSherlock(t *testing.T) {
+	data, err := os.ReadFile("testdata/sherlock.txt")
+	require.NoError(t, err)
+	m, err := NewMarkov(2)
+	require.NoError(t, err)
+	m.Add(string(data))
+
+	opts := GenerateOptions{Prompt: "Sherlock Holmes", Sentences: 2, MaxTokens: 50}
+	text1, err := m.Generate(rand.New(rand.NewPCG(42, 0)), opts)
+	require.NoError(t, err)
+	text2, err := m.Generate(rand.New(rand.NewPCG(42, 0)), opts)
+	require.NoError(t, err)
+	require.Equal(t, text1, text2) // Same seed, same text.
+	require.True(t, strings.HasPrefix(text1, "Sherlock Holmes"), text1)
+	require.LessOrEqual(t, len(markovTokenRe.FindAllString(text1, -1)), 52)
+
+	// Save and load.
+	var buf bytes.Buffer
+	require.NoError(t, m.Save(&buf))
+	m2, err := LoadMarkov(&buf)
+	require.NoError(t, err)
+	require.Equal(t, m, m2)
+}
+
+func TestLoadMarkovErrors(t *testing.T) {
+	for _, data := range []string{
+		"",
+		"order x\n",
+		"order 0\n",
+		"order 1\n\"a\" 3\n",
+		"order 1\n\"a\" \"b\" 0\n",
+		"order 1\na b 1\n",
+	} {
+		_, err := LoadMarkov(strings.NewReader(data))
+		require.Error(t, err, data)
+	}
+}
+
+func ExampleMarkov_Generate() {
+	m, _ := NewMarkov(2)
+	m.Add("I have seen it. I have heard it, and I have no doubt.")
+
+	rng := rand.New(rand.NewPCG(1, 1))
+	text, _ := m.Generate(rng, GenerateOptions{Prompt: "I have", Temperature: 0.01})
+	fmt.Println(text)
+
+	// Output:
+	// I have no doubt.
+}
@@ -1,6 +1,7 @@
 package nlp
 
 import (
+	"math/rand/v2"
 	"os"
 	"strings"
 	"testing"
@@ -153,6 +154,19 @@ i.e., exactly the correct tokens for the sentence it is "tokenizing". */
 func FuzzTokenizer(f *testing.F) {
 	f.Add("") // Manually test a specific test case every time this test runs (in this case, tokenizing an empty string).
 
+	// Seed the fuzzer with plausible text too, generated by a Markov model of the book (see markov.go).
+	data, err := os.ReadFile("testdata/sherlock.txt")
+	require.NoError(f, err)
+	m, err := NewMarkov(2)
+	require.NoError(f, err)
+	m.Add(string(data))
+	rng := rand.New(rand.NewPCG(1, 2))
+	for range 10 {
+		text, err := m.Generate(rng, GenerateOptions{Sentences: 3})
+		require.NoError(f, err)
+		f.Add(text)
+	}
+
 	// Fuzz test.
 	fn := func(t *testing.T, text string) {
 		tokens := Tokenize(text)
@@ -0,0 +1,158 @@
+package nlp
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	unicodenorm "golang.org/x/text/unicode/norm"
+)
+
+/*
+Text normalization.
+The same word can be written in several ways in Unicode, e.g. "é" can be a single rune (U+00E9) or "e" followed by a combining
+acute accent (U+0065 U+0301), and "ｗｏｒｄ" (full-width letters) is "word" for a reader.
+Normalizing the text before splitting it into words makes these variations produce the same tokens.
+
+The normalization forms and case folding come from golang.org/x/text, which implements them for all of Unicode.
+*/
+
+// Form is a Unicode normalization form.
+type Form int
+
+const (
+	NFC  Form = iota // Canonical composition.
+	NFD              // Canonical decomposition.
+	NFKC             // Compatibility composition, e.g. "ﬁ" -> "fi", "ｗ" -> "w".
+	NFKD             // Compatibility decomposition.
+)
+
+var formNames = map[Form]string{
+	NFC:  "NFC",
+	NFD:  "NFD",
+	NFKC: "NFKC",
+	NFKD: "NFKD",
+}
+
+// String implements fmt.Stringer.
+func (f Form) String() string {
+	if name, ok := formNames[f]; ok {
+		return name
+	}
+	return fmt.Sprintf("Form(%d)", int(f))
+}
+
+// ParseForm returns the Form for name (e.g. "NFKC").
+func ParseForm(name string) (Form, error) {
+	for f, n := range formNames {
+		if strings.EqualFold(n, name) {
+			return f, nil
+		}
+	}
+	return NFC, fmt.Errorf("unknown normalization form: %q", name)
+}
+
+// Normalizer normalizes text. The zero value does NFC normalization.
+type Normalizer struct {
+	Form            Form
+	CaseFold        bool // Unicode case folding, e.g. "Straße" -> "strasse".
+	StripAccents    bool // "résumé" -> "resume".
+	ExpandLigatures bool // "ﬁ" -> "fi", "æ" -> "ae".
+}
+
+var (
+	// accentless are letters that don't decompose, but that are read as a letter with an accent.
+	accentless = map[rune]rune{
+		'ø': 'o', 'Ø': 'O',
+		'ł': 'l', 'Ł': 'L',
+		'đ': 'd', 'Đ': 'D',
+		'ħ': 'h', 'Ħ': 'H',
+		'ı': 'i',
+	}
+
+	ligatures = map[rune]string{
+		'ﬀ': "ff", 'ﬁ': "fi", 'ﬂ': "fl", 'ﬃ': "ffi", 'ﬄ': "ffl", 'ﬅ': "st", 'ﬆ': "st",
+		'æ': "ae", 'Æ': "AE",
+		'œ': "oe", 'Œ': "OE",
+		'ĳ': "ij", 'Ĳ': "IJ",
+	}
+
+	// diacriticalMarks are the combining marks StripAccents removes: the combining diacritical mark blocks.
+	// Other non-spacing marks are part of the letters of their script (e.g. Devanagari vowel signs and virama),
+	// and removing them would change the words.
+	diacriticalMarks = &unicode.RangeTable{
+		R16: []unicode.Range16{
+			{Lo: 0x0300, Hi: 0x036F, Stride: 1}, // Combining Diacritical Marks.
+			{Lo: 0x1AB0, Hi: 0x1AFF, Stride: 1}, // Combining Diacritical Marks Extended.
+			{Lo: 0x1DC0, Hi: 0x1DFF, Stride: 1}, // Combining Diacritical Marks Supplement.
+			{Lo: 0x20D0, Hi: 0x20FF, Stride: 1}, // Combining Diacritical Marks for Symbols.
+			{Lo: 0xFE20, Hi: 0xFE2F, Stride: 1}, // Combining Half Marks.
+		},
+	}
+)
+
+// unicodeForm returns the golang.org/x/text normalization form for f.
+func (f Form) unicodeForm() unicodenorm.Form {
+	switch f {
+	case NFD:
+		return unicodenorm.NFD
+	case NFKC:
+		return unicodenorm.NFKC
+	case NFKD:
+		return unicodenorm.NFKD
+	}
+	return unicodenorm.NFC
+}
+
+// Normalize returns the normalized version of text.
+func (n Normalizer) Normalize(text string) string {
+	if isASCII(text) {
+		if n.CaseFold {
+			return strings.ToLower(text)
+		}
+		return text
+	}
+
+	if n.ExpandLigatures {
+		var b strings.Builder
+		for _, r := range text {
+			if s, ok := ligatures[r]; ok {
+				b.WriteString(s)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+		text = b.String()
+	}
+	if n.CaseFold {
+		text = cases.Fold().String(text) // A Caser keeps state, it can't be shared.
+	}
+	if n.StripAccents {
+		text = strings.Map(func(r rune) rune {
+			if unicode.Is(diacriticalMarks, r) {
+				return -1
+			}
+			if a, ok := accentless[r]; ok {
+				return a
+			}
+			return r
+		}, unicodenorm.NFD.String(text))
+	}
+	return n.Form.unicodeForm().String(text)
+}
+
+// CharFilter returns n.Normalize as a CharFilter, to use it in an Analyzer.
+func (n Normalizer) CharFilter() CharFilter {
+	return n.Normalize
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
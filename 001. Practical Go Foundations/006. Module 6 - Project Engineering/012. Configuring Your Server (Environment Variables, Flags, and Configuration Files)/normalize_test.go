@@ -0,0 +1,76 @@
+package nlp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	var cases = []struct {
+		name string
+		n    Normalizer
+		in   string
+		out  string
+	}{
+		{"nfc", Normalizer{}, "re\u0301sume\u0301", "r\u00e9sum\u00e9"},
+		{"nfd", Normalizer{Form: NFD}, "r\u00e9sum\u00e9", "re\u0301sume\u0301"},
+		{"nfc order", Normalizer{}, "a\u0323\u0302", "\u1ead"}, // a + dot below + circumflex.
+		{"nfc reorder", Normalizer{}, "a\u0302\u0323", "\u1ead"},
+		{"nfkc full width", Normalizer{Form: NFKC}, "ｗｏｒｄ", "word"},
+		{"nfkc ligature", Normalizer{Form: NFKC}, "ﬁnd", "find"},
+		{"nfc keeps ligature", Normalizer{}, "ﬁnd", "ﬁnd"},
+		{"case fold", Normalizer{CaseFold: true}, "Stra\u00dfe \u03a3\u038a\u03a3\u03a5\u03a6\u039f\u03a3", "strasse \u03c3\u03af\u03c3\u03c5\u03c6\u03bf\u03c3"},
+		{"strip accents", Normalizer{StripAccents: true}, "Résumé naïve Łódź", "Resume naive Lodz"},
+		{"ligatures", Normalizer{ExpandLigatures: true}, "Æsop's œuvre ﬁle", "AEsop's oeuvre file"},
+		{"ascii", Normalizer{CaseFold: true}, "Who's on First?", "who's on first?"},
+		{"nfc en quad", Normalizer{}, "a\u2000b", "a\u2002b"},
+		{"nfkc greek symbols", Normalizer{Form: NFKC}, "\u03d0\u037a\u0384\u03f0\u03f1\u03f2\u03f4\u03f5", "\u03b2 \u0345 \u0301\u03ba\u03c1\u03c2\u0398\u03b5"},
+		{"nfd hangul", Normalizer{Form: NFD}, "한", "\u1112\u1161\u11ab"},
+		{"nfc hangul", Normalizer{}, "\u1112\u1161\u11ab", "한"},
+		{"nfc hebrew", Normalizer{}, "\u05e9\u05c1", "\u05e9\u05c1"},
+		{"nfkc arabic", Normalizer{Form: NFKC}, "\ufefb", "\u0644\u0627"},
+		{"strip accents keeps indic marks", Normalizer{StripAccents: true}, "हिन्दी", "हिन्दी"},
+		{"strip accents greek", Normalizer{StripAccents: true}, "Αθήνα", "Αθηνα"},
+		{"case fold final sigma", Normalizer{CaseFold: true}, "ΟΔΟΣ οδος", "οδοσ οδοσ"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.out, tc.n.Normalize(tc.in))
+		})
+	}
+}
+
+func TestParseForm(t *testing.T) {
+	f, err := ParseForm("nfkc")
+	require.NoError(t, err)
+	require.Equal(t, NFKC, f)
+
+	_, err = ParseForm("nfx")
+	require.Error(t, err)
+}
+
+func TestAnalyzer(t *testing.T) {
+	// Composed and decomposed forms give different tokens without normalization.
+	composed, decomposed := "R\u00e9sum\u00e9", "Re\u0301sume\u0301"
+	require.NotEqual(t, DefaultAnalyzer.Tokenize(composed), DefaultAnalyzer.Tokenize(decomposed))
+
+	a := Analyzer{
+		CharFilters: []CharFilter{Normalizer{Form: NFKC, CaseFold: true}.CharFilter()},
+		Filters:     []Filter{StemFilter},
+	}
+	require.Equal(t, []string{"r\u00e9sum\u00e9"}, a.Tokenize(composed))
+	require.Equal(t, []string{"r\u00e9sum\u00e9"}, a.Tokenize(decomposed))
+	require.Equal(t, []string{"word"}, a.Tokenize("ＷＯＲＤＳ"))
+
+	a.CharFilters = []CharFilter{Normalizer{Form: NFKC, CaseFold: true, StripAccents: true}.CharFilter()}
+	require.Equal(t, []string{"resume"}, a.Tokenize(decomposed))
+}
+
+func TestDefaultAnalyzer(t *testing.T) {
+	// Same as Tokenize for ASCII text.
+	for _, text := range []string{"Who's on first?", "What's on second?", ""} {
+		require.Equal(t, Tokenize(text), DefaultAnalyzer.Tokenize(text))
+	}
+}
@@ -0,0 +1,437 @@
+package nlp
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+/*
+Numbers.
+"205", "two hundred and five" and "two hundred five" are the same number, search should find one with the other.
+Text-to-speech needs the opposite: "205" must be read out as words.
+Number words have a small grammar: a group under a thousand ("two hundred and five") is followed by a scale ("million"),
+and scales go down ("two million three thousand"). A word that doesn't fit the grammar starts a new number,
+so "nineteen eighty-four" is 19 and 84, and "one two three" is 1, 2 and 3.
+Numbers are verbalized the American way, without "and": 205 is "two hundred five".
+*/
+
+// maxFractionDigits is the number of digits after the point NumberWords says.
+const maxFractionDigits = 6
+
+var (
+	// NumberTokenRe matches words in any script, and numbers such as "1,234.5" or "3rd", to use as an Analyzer's Pattern.
+	NumberTokenRe = regexp.MustCompile(`\p{L}[\p{L}\p{M}]*|` + numPattern + `(?:st|nd|rd|th)?`)
+	// digitsRe matches a number in digits, with an optional ordinal suffix.
+	digitsRe = regexp.MustCompile(`^(` + numPattern + `)(st|nd|rd|th)?$`)
+
+	smallNumberWords = [...]string{
+		"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine", "ten",
+		"eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen", "seventeen", "eighteen", "nineteen",
+	}
+	tensWords = [...]string{2: "twenty", 3: "thirty", 4: "forty", 5: "fifty", 6: "sixty", 7: "seventy", 8: "eighty", 9: "ninety"}
+
+	// scaleWords are the scales, from the largest.
+	scaleWords = []struct {
+		value uint64
+		word  string
+	}{
+		{1e18, "quintillion"}, {1e15, "quadrillion"}, {1e12, "trillion"}, {1e9, "billion"}, {1e6, "million"}, {1e3, "thousand"},
+	}
+
+	// irregularOrdinals are the ordinals that don't just add "th" to the cardinal.
+	irregularOrdinals = map[string]string{
+		"one": "first", "two": "second", "three": "third", "five": "fifth", "eight": "eighth", "nine": "ninth", "twelve": "twelfth",
+	}
+
+	numberWords = make(map[string]numberWord) // Cardinal and ordinal words.
+)
+
+// numberCategory is the category of a number word, for the grammar.
+type numberCategory int
+
+const (
+	noNumber numberCategory = iota
+	zeroNumber
+	unitNumber // 1-9.
+	teenNumber // 10-19.
+	tensNumber // 20, 30... 90.
+	hundredNumber
+	scaleNumber
+	digitsNumber // "1.5".
+)
+
+type numberWord struct {
+	category numberCategory
+	value    float64
+	ordinal  bool
+	digits   string // For digitsNumber, the number without commas, to get its exact value.
+}
+
+// rat returns the exact value of w.
+func (w numberWord) rat() *big.Rat {
+	if w.category == digitsNumber {
+		if r, ok := new(big.Rat).SetString(w.digits); ok {
+			return r
+		}
+	}
+	return new(big.Rat).SetFloat64(w.value) // Number words are integers, exact in a float64.
+}
+
+func init() {
+	add := func(word string, category numberCategory, value float64) {
+		numberWords[word] = numberWord{category: category, value: value}
+		numberWords[ordinalWord(word)] = numberWord{category: category, value: value, ordinal: true}
+	}
+	for i, w := range smallNumberWords {
+		switch {
+		case i == 0:
+			add(w, zeroNumber, 0)
+		case i < 10:
+			add(w, unitNumber, float64(i))
+		default:
+			add(w, teenNumber, float64(i))
+		}
+	}
+	for i, w := range tensWords {
+		if w != "" {
+			add(w, tensNumber, float64(i*10))
+		}
+	}
+	add("hundred", hundredNumber, 100)
+	for _, s := range scaleWords {
+		add(s.word, scaleNumber, float64(s.value))
+	}
+}
+
+// ordinalWord returns the ordinal of a cardinal number word, e.g. "twenty" -> "twentieth".
+func ordinalWord(word string) string {
+	if o, ok := irregularOrdinals[word]; ok {
+		return o
+	}
+	if w, ok := strings.CutSuffix(word, "y"); ok {
+		return w + "ieth"
+	}
+	return word + "th"
+}
+
+// numberParser parses number words, one token at a time.
+// Values are exact fractions, so that "4.1 million" is 4100000 and not 4099999.9999999995.
+type numberParser struct {
+	total     big.Rat // Value of the groups before the last scale.
+	current   big.Rat // Value of the current group.
+	lastScale float64 // Smallest scale so far, the next one must be smaller.
+	last      numberCategory
+}
+
+// add adds a number word, and reports whether it continues the number.
+func (p *numberParser) add(w numberWord) bool {
+	last := p.last
+	afterGroup := last == noNumber || last == hundredNumber || last == scaleNumber
+	switch w.category {
+	case zeroNumber:
+		if last != noNumber {
+			return false
+		}
+	case unitNumber:
+		if !afterGroup && last != tensNumber {
+			return false
+		}
+		p.current.Add(&p.current, w.rat())
+	case teenNumber, tensNumber:
+		if !afterGroup {
+			return false
+		}
+		p.current.Add(&p.current, w.rat())
+	case hundredNumber:
+		if last != unitNumber && last != teenNumber && last != tensNumber && last != digitsNumber {
+			return false
+		}
+		p.current.Mul(&p.current, big.NewRat(100, 1))
+	case scaleNumber:
+		if last == noNumber || last == scaleNumber || last == zeroNumber || (p.lastScale != 0 && w.value >= p.lastScale) {
+			return false
+		}
+		p.total.Add(&p.total, new(big.Rat).Mul(&p.current, w.rat()))
+		p.current.SetInt64(0)
+		p.lastScale = w.value
+	case digitsNumber:
+		if last != noNumber {
+			return false
+		}
+		p.current.Set(w.rat())
+	}
+	p.last = w.category
+	return true
+}
+
+func (p *numberParser) value() *big.Rat {
+	return new(big.Rat).Add(&p.total, &p.current)
+}
+
+// parseNumberTokens parses the longest number at the start of tokens, and returns its value,
+// whether it's an ordinal, and the number of tokens it takes (0 if tokens don't start with a number).
+func parseNumberTokens(tokens []string) (*big.Rat, bool, int) {
+	var p numberParser
+	value, n := new(big.Rat), 0
+	wordAt := func(i int) (numberWord, bool) {
+		if i >= len(tokens) {
+			return numberWord{}, false
+		}
+		w, ok := numberWords[strings.ToLower(tokens[i])]
+		return w, ok
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		tok := strings.ToLower(tokens[i])
+		switch {
+		case tok == "and": // "two hundred and five", only before a group.
+			next, ok := wordAt(i + 1)
+			if (p.last != hundredNumber && p.last != scaleNumber) || !ok || next.category < unitNumber || next.category > tensNumber {
+				return value, false, n
+			}
+			continue
+		case tok == "a" && i == 0: // "a hundred", "a million".
+			next, ok := wordAt(i + 1)
+			if !ok || (next.category != hundredNumber && next.category != scaleNumber) {
+				return value, false, n
+			}
+			p.current.SetInt64(1)
+			p.last = unitNumber
+			continue
+		case tok == "point" && p.last != noNumber && p.last != digitsNumber: // "one point five".
+			digits := ""
+			for j := i + 1; j < len(tokens); j++ {
+				w, ok := wordAt(j)
+				if !ok || w.ordinal || w.category > unitNumber {
+					break
+				}
+				digits += strconv.Itoa(int(w.value))
+			}
+			if digits == "" {
+				return value, false, n
+			}
+			frac, _ := new(big.Rat).SetString("0." + digits)
+			return frac.Add(frac, p.value()), false, i + 1 + len(digits)
+		}
+
+		var w numberWord
+		if m := digitsRe.FindStringSubmatch(tok); m != nil {
+			v, err := parseNumber(m[1])
+			if err != nil {
+				return value, false, n
+			}
+			w = numberWord{category: digitsNumber, value: v, ordinal: m[2] != "", digits: strings.ReplaceAll(m[1], ",", "")}
+		} else if w, _ = wordAt(i); w.category == noNumber {
+			return value, false, n
+		}
+		if p.last == noNumber && w.ordinal && (w.category == hundredNumber || w.category == scaleNumber) {
+			p.current.SetInt64(1) // "hundredth", "millionth".
+			p.last = unitNumber
+		}
+		if !p.add(w) {
+			return value, false, n
+		}
+		value, n = p.value(), i+1
+		if w.ordinal || (w.category == zeroNumber && !(i+1 < len(tokens) && strings.EqualFold(tokens[i+1], "point"))) {
+			return value, w.ordinal, n
+		}
+	}
+	return value, false, n
+}
+
+// ParseNumberWords parses a number in words or digits, e.g. "two hundred and five", "twenty-third", "3rd" or "1.5 million",
+// and reports whether it's an ordinal.
+func ParseNumberWords(s string) (float64, bool, error) {
+	tokens := NumberTokenRe.FindAllString(s, -1)
+	value, ordinal, n := parseNumberTokens(tokens)
+	if n == 0 || n != len(tokens) {
+		return 0, false, fmt.Errorf("not a number: %q", s)
+	}
+	f, _ := value.Float64()
+	return f, ordinal, nil
+}
+
+// NumberWords returns v in words, e.g. 205 -> "two hundred five", 1.5 -> "one point five", -23 -> "minus twenty-three".
+// The fraction is rounded to maxFractionDigits digits. Magnitudes it can't say in words (too large for a uint64,
+// or too small to show in maxFractionDigits digits) are returned in digits, e.g. "1e+21".
+func NumberWords(v float64) string {
+	switch {
+	case math.IsNaN(v):
+		return "not a number"
+	case math.IsInf(v, 1):
+		return "infinity"
+	case math.IsInf(v, -1):
+		return "minus infinity"
+	}
+
+	abs := math.Abs(v)
+	if abs != 0 && abs < math.Pow10(-maxFractionDigits) {
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	intPart, frac, _ := strings.Cut(strconv.FormatFloat(abs, 'f', maxFractionDigits, 64), ".")
+	n, err := strconv.ParseUint(intPart, 10, 64)
+	if err != nil {
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+
+	var words []string
+	if v < 0 {
+		words = append(words, "minus")
+	}
+	words = append(words, integerWords(n)...)
+	if frac = strings.TrimRight(frac, "0"); frac != "" {
+		words = append(words, "point")
+		words = append(words, digitWords(frac)...)
+	}
+	return strings.Join(words, " ")
+}
+
+// OrdinalWords returns the ordinal of n in words, e.g. 23 -> "twenty-third".
+func OrdinalWords(n uint64) string {
+	words := integerWords(n)
+	last := words[len(words)-1]
+	if tens, unit, ok := strings.Cut(last, "-"); ok {
+		words[len(words)-1] = tens + "-" + ordinalWord(unit)
+	} else {
+		words[len(words)-1] = ordinalWord(last)
+	}
+	return strings.Join(words, " ")
+}
+
+// integerWords returns the words of n, "twenty-three" is one word.
+func integerWords(n uint64) []string {
+	if n == 0 {
+		return []string{"zero"}
+	}
+	var words []string
+	for _, s := range scaleWords {
+		if n >= s.value {
+			words = append(words, groupWords(n/s.value)...)
+			words = append(words, s.word)
+			n %= s.value
+		}
+	}
+	return append(words, groupWords(n)...)
+}
+
+// groupWords returns the words of n < 1000, nothing for 0.
+func groupWords(n uint64) []string {
+	var words []string
+	if n >= 100 {
+		words = append(words, smallNumberWords[n/100], "hundred")
+		n %= 100
+	}
+	switch {
+	case n == 0:
+	case n < 20:
+		words = append(words, smallNumberWords[n])
+	case n%10 == 0:
+		words = append(words, tensWords[n/10])
+	default:
+		words = append(words, tensWords[n/10]+"-"+smallNumberWords[n%10])
+	}
+	return words
+}
+
+func digitWords(digits string) []string {
+	words := make([]string, len(digits))
+	for i, d := range digits {
+		words[i] = smallNumberWords[d-'0']
+	}
+	return words
+}
+
+// ordinalSuffix returns the suffix of n in digits, e.g. "st" for 21.
+func ordinalSuffix(n uint64) string {
+	if n%100 >= 11 && n%100 <= 13 {
+		return "th"
+	}
+	switch n % 10 {
+	case 1:
+		return "st"
+	case 2:
+		return "nd"
+	case 3:
+		return "rd"
+	}
+	return "th"
+}
+
+// NumberFilter replaces numbers, in words or digits, by their value in digits, for search:
+// "two", "hundred", "and", "five" -> "205", "third" -> "3rd", "1.5", "million" -> "1500000".
+// Use an Analyzer with NumberTokenRe as its Pattern to keep the numbers in digits.
+func NumberFilter(tokens []string) []string {
+	var out []string
+	for i := 0; i < len(tokens); {
+		value, ordinal, n := parseNumberTokens(tokens[i:])
+		if n == 0 {
+			out = append(out, tokens[i])
+			i++
+			continue
+		}
+		num := decimalString(value)
+		if ordinal && value.IsInt() && value.Num().IsUint64() {
+			num += ordinalSuffix(value.Num().Uint64())
+		}
+		out = append(out, num)
+		i += n
+	}
+	return out
+}
+
+// decimalString returns r in digits, with as many digits after the point as it needs (r is a decimal fraction,
+// as parsed from words and digits).
+func decimalString(r *big.Rat) string {
+	if r.IsInt() {
+		return r.Num().String()
+	}
+	const maxPrec = 64
+	for prec := 1; prec < maxPrec; prec++ {
+		s := r.FloatString(prec)
+		if exact, _ := new(big.Rat).SetString(s); exact.Cmp(r) == 0 {
+			return s
+		}
+	}
+	return r.FloatString(maxPrec)
+}
+
+// VerbalizeFilter replaces numbers in digits by words, for text-to-speech: "205" -> "two", "hundred", "five",
+// "3rd" -> "third", "1,234.5" -> "one", "thousand", "two", "hundred", "thirty", "four", "point", "five".
+// Numbers NumberWords can't say in words (e.g. "0.0000001", or ordinals past the uint64 range) are kept as they are.
+func VerbalizeFilter(tokens []string) []string {
+	var out []string
+	for _, tok := range tokens {
+		m := digitsRe.FindStringSubmatch(tok)
+		if m == nil {
+			out = append(out, tok)
+			continue
+		}
+
+		var words string
+		digits := strings.ReplaceAll(m[1], ",", "")
+		if n, err := strconv.ParseUint(digits, 10, 64); err == nil { // Exact, even past 2^53.
+			if m[2] != "" {
+				words = OrdinalWords(n)
+			} else {
+				words = strings.Join(integerWords(n), " ")
+			}
+		} else if value, err := strconv.ParseFloat(digits, 64); err == nil {
+			switch {
+			case m[2] == "" || value != math.Trunc(value):
+				words = NumberWords(value)
+			case value < 1<<64: // "3.0th".
+				words = OrdinalWords(uint64(value))
+			}
+		}
+		if words == "" || strings.ContainsAny(words, "0123456789") { // Too large, or too small.
+			out = append(out, tok)
+			continue
+		}
+		out = append(out, strings.FieldsFunc(words, func(r rune) bool { return r == ' ' || r == '-' })...)
+	}
+	return out
+}
@@ -0,0 +1,144 @@
+package nlp
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNumberWords(t *testing.T) {
+	var cases = []struct {
+		text    string
+		value   float64
+		ordinal bool
+	}{
+		{"zero", 0, false},
+		{"seven", 7, false},
+		{"twenty-three", 23, false},
+		{"two hundred and five", 205, false},
+		{"Two Hundred Five", 205, false},
+		{"a hundred", 100, false},
+		{"nineteen hundred", 1900, false},
+		{"three million two hundred thousand and one", 3200001, false},
+		{"1.5 million", 1500000, false},
+		{"1,234", 1234, false},
+		{"one point five", 1.5, false},
+		{"zero point two five", 0.25, false},
+		{"3rd", 3, true},
+		{"first", 1, true},
+		{"twenty-first", 21, true},
+		{"one hundred and twelfth", 112, true},
+		{"millionth", 1000000, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.text, func(t *testing.T) {
+			value, ordinal, err := ParseNumberWords(tc.text)
+			require.NoError(t, err)
+			require.Equal(t, tc.value, value)
+			require.Equal(t, tc.ordinal, ordinal)
+		})
+	}
+}
+
+func TestParseNumberWordsErrors(t *testing.T) {
+	for _, text := range []string{"", "hello", "one two", "twenty twelve", "hundred", "and five", "thousand million", "five and", "first second"} {
+		_, _, err := ParseNumberWords(text)
+		require.Error(t, err, text)
+	}
+}
+
+func TestNumberWords(t *testing.T) {
+	var cases = []struct {
+		value    float64
+		expected string
+	}{
+		{0, "zero"},
+		{13, "thirteen"},
+		{40, "forty"},
+		{205, "two hundred five"},
+		{-23, "minus twenty-three"},
+		{1.05, "one point zero five"},
+		{1_000_000, "one million"},
+		{2_001_019, "two million one thousand nineteen"},
+		{1e21, "1e+21"},
+		{-1e-300, "-1e-300"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.expected, func(t *testing.T) {
+			words := NumberWords(tc.value)
+			require.Equal(t, tc.expected, words)
+			if tc.value >= 0 && tc.value < 1e18 { // Round trip.
+				value, _, err := ParseNumberWords(words)
+				require.NoError(t, err)
+				require.Equal(t, tc.value, value)
+			}
+		})
+	}
+}
+
+func TestNumberWordsRounding(t *testing.T) {
+	require.Equal(t, "zero point three", NumberWords(0.1+0.2))
+	require.Equal(t, "three point one four one five nine three", NumberWords(3.14159265))
+	require.Equal(t, "minus one", NumberWords(-0.9999999))
+}
+
+func TestOrdinalWords(t *testing.T) {
+	for n, expected := range map[uint64]string{
+		1: "first", 2: "second", 3: "third", 5: "fifth", 9: "ninth", 12: "twelfth", 20: "twentieth",
+		23: "twenty-third", 100: "one hundredth", 1000: "one thousandth", 101: "one hundred first",
+	} {
+		require.Equal(t, expected, OrdinalWords(n))
+		value, ordinal, err := ParseNumberWords(expected)
+		require.NoError(t, err)
+		require.True(t, ordinal)
+		require.Equal(t, float64(n), value)
+	}
+	for n, suffix := range map[uint64]string{1: "st", 2: "nd", 3: "rd", 4: "th", 11: "th", 12: "th", 13: "th", 21: "st", 112: "th", 122: "nd"} {
+		require.Equal(t, suffix, ordinalSuffix(n), n)
+	}
+}
+
+func TestNumberFilter(t *testing.T) {
+	a := Analyzer{Pattern: NumberTokenRe, Filters: []Filter{LowerCaseFilter, NumberFilter, StemFilter}}
+	require.Equal(t, []string{"205", "book"}, a.Tokenize("Two hundred and five books"))
+	require.Equal(t, []string{"205", "book"}, a.Tokenize("205 books"))
+	require.Equal(t, []string{"the", "3rd", "man"}, a.Tokenize("The third man"))
+	require.Equal(t, []string{"the", "3rd", "man"}, a.Tokenize("The 3rd man"))
+	require.Equal(t, []string{"1500000", "people"}, a.Tokenize("1.5 million people"))
+	require.Equal(t, []string{"1984", "and", "19", "84"}, a.Tokenize("1984 and nineteen eighty-four"))
+
+	// Scaled decimals are exact.
+	require.Equal(t, []string{"4100000"}, a.Tokenize("4.1 million"))
+	require.Equal(t, []string{"2010"}, a.Tokenize("2.01 thousand"))
+	require.Equal(t, []string{"1005"}, a.Tokenize("1.005 thousand"))
+	require.Equal(t, []string{"2.5"}, a.Tokenize("two point five"))
+	require.Equal(t, []string{"1000000.5"}, a.Tokenize("1,000,000.5"))
+}
+
+func TestVerbalizeFilter(t *testing.T) {
+	a := Analyzer{Pattern: NumberTokenRe, Filters: []Filter{VerbalizeFilter}}
+	require.Equal(t, strings.Fields("Chapter twenty three"), a.Tokenize("Chapter 23"))
+	require.Equal(t, strings.Fields("the twenty first of one thousand two hundred thirty four point five"), a.Tokenize("the 21st of 1,234.5"))
+
+	// Numbers that can't be said in words stay in digits, as one token.
+	require.Equal(t, []string{"99999999999999999999th"}, a.Tokenize("99999999999999999999th"))
+	require.Equal(t, []string{"18446744073709551616th"}, a.Tokenize("18446744073709551616th"))
+	require.Equal(t, []string{"0.0000001"}, a.Tokenize("0.0000001"))
+	require.Equal(t, []string{"third"}, a.Tokenize("3.0th"))
+	require.Equal(t, strings.Fields("eighteen quintillion four hundred forty six quadrillion seven hundred forty four trillion seventy three billion seven hundred nine million five hundred fifty one thousand six hundred fifteenth"),
+		a.Tokenize("18446744073709551615th"))
+}
+
+func ExampleNumberWords() {
+	fmt.Println(NumberWords(221))
+	fmt.Println(OrdinalWords(221))
+	value, ordinal, _ := ParseNumberWords("two hundred and twenty-first")
+	fmt.Println(value, ordinal)
+
+	// Output:
+	// two hundred twenty-one
+	// two hundred twenty-first
+	// 221 true
+}
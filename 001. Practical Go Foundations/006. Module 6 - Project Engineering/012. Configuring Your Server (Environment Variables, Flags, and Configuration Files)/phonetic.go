@@ -0,0 +1,774 @@
+package nlp
+
+import (
+	"strings"
+)
+
+/*
+Phonetic encoders.
+Stemming maps "running" and "runs" to the same token, phonetic encoders do the same for words that sound alike:
+"Smith" and "Smyth", or "Catherine" and "Kathryn" get the same code, so misspelled names still match.
+All encoders are for English (and English spellings of names), and ignore everything but the letters A to Z,
+after accents are removed ("Müller" is encoded as "Muller").
+*/
+
+// PhoneticEncoders maps algorithm names to encoders.
+var PhoneticEncoders = map[string]func(string) string{
+	"soundex":              Soundex,
+	"nysiis":               NYSIIS,
+	"metaphone":            Metaphone,
+	"double_metaphone":     func(word string) string { p, _ := DoubleMetaphone(word); return p },
+	"double_metaphone_alt": func(word string) string { _, s := DoubleMetaphone(word); return s },
+}
+
+// PhoneticFilter returns a Filter that replaces every token with its code, e.g. PhoneticFilter(Soundex).
+func PhoneticFilter(encode func(string) string) Filter {
+	return MapFilter(encode)
+}
+
+// DoubleMetaphoneFilter replaces every token with its Double Metaphone codes: the primary code,
+// followed by the alternate code if it's different.
+func DoubleMetaphoneFilter(tokens []string) []string {
+	out := make([]string, 0, len(tokens))
+	for _, tok := range tokens {
+		primary, alternate := DoubleMetaphone(tok)
+		out = append(out, primary)
+		if alternate != primary {
+			out = append(out, alternate)
+		}
+	}
+	return out
+}
+
+var accentStripper = Normalizer{Form: NFD, StripAccents: true}
+
+// phoneticLetters returns word without accents, upper cased, and with only the letters A to Z (and spaces if keepSpaces).
+func phoneticLetters(word string, keepSpaces bool) string {
+	word = strings.ToUpper(accentStripper.Normalize(word))
+	return strings.Map(func(r rune) rune {
+		if (r >= 'A' && r <= 'Z') || (keepSpaces && r == ' ') {
+			return r
+		}
+		return -1
+	}, word)
+}
+
+func isVowel(c byte) bool {
+	return strings.IndexByte("AEIOU", c) >= 0
+}
+
+// soundexCodes are the Soundex digits of the letters A to Z. '0' is a vowel (including Y), ' ' is H or W.
+const soundexCodes = "0123012 02245501262301 202"
+
+// Soundex returns the American Soundex code of word: its first letter and three digits, e.g. "R163" for "Robert".
+// It returns "" if word has no letters.
+func Soundex(word string) string {
+	letters := phoneticLetters(word, false)
+	if letters == "" {
+		return ""
+	}
+
+	code := []byte{letters[0]}
+	prev := soundexCodes[letters[0]-'A']
+	for i := 1; i < len(letters) && len(code) < 4; i++ {
+		c := soundexCodes[letters[i]-'A']
+		switch {
+		case c == ' ': // H and W don't separate letters with the same code.
+		case c == '0': // Vowels do.
+			prev = c
+		case c != prev:
+			code = append(code, c)
+			prev = c
+		}
+	}
+	for len(code) < 4 {
+		code = append(code, '0')
+	}
+	return string(code)
+}
+
+var (
+	nysiisPrefixes = []struct{ from, to string }{
+		{"MAC", "MCC"}, {"KN", "NN"}, {"K", "C"}, {"PH", "FF"}, {"PF", "FF"}, {"SCH", "SSS"},
+	}
+	nysiisSuffixes = []struct{ from, to string }{
+		{"EE", "Y"}, {"IE", "Y"}, {"DT", "D"}, {"RT", "D"}, {"RD", "D"}, {"NT", "D"}, {"ND", "D"},
+	}
+)
+
+// nysiisMaxLen is the length of NYSIIS codes in the original algorithm.
+const nysiisMaxLen = 6
+
+// NYSIIS returns the New York State Identification and Intelligence System code of word, e.g. "SNAT" for "Smith".
+// It returns "" if word has no letters.
+func NYSIIS(word string) string {
+	letters := phoneticLetters(word, false)
+	if letters == "" {
+		return ""
+	}
+
+	for _, p := range nysiisPrefixes {
+		if rest, ok := strings.CutPrefix(letters, p.from); ok {
+			letters = p.to + rest
+			break
+		}
+	}
+	for _, s := range nysiisSuffixes {
+		if rest, ok := strings.CutSuffix(letters, s.from); ok {
+			letters = rest + s.to
+			break
+		}
+	}
+
+	chars := []byte(letters)
+	at := func(i int) byte {
+		if i < len(chars) {
+			return chars[i]
+		}
+		return ' '
+	}
+	key := []byte{chars[0]}
+	for i := 1; i < len(chars); i++ {
+		prev, cur, next, next2 := chars[i-1], chars[i], at(i+1), at(i+2)
+
+		var sub string
+		switch {
+		case cur == 'E' && next == 'V':
+			sub = "AF"
+		case isVowel(cur):
+			sub = "A"
+		case cur == 'Q':
+			sub = "G"
+		case cur == 'Z':
+			sub = "S"
+		case cur == 'M':
+			sub = "N"
+		case cur == 'K' && next == 'N':
+			sub = "NN"
+		case cur == 'K':
+			sub = "C"
+		case cur == 'S' && next == 'C' && next2 == 'H':
+			sub = "SSS"
+		case cur == 'P' && next == 'H':
+			sub = "FF"
+		case cur == 'H' && (!isVowel(prev) || !isVowel(next)):
+			sub = string(prev)
+		case cur == 'W' && isVowel(prev):
+			sub = string(prev)
+		default:
+			sub = string(cur)
+		}
+		copy(chars[i:], sub) // The substitution replaces the following letters too.
+
+		if chars[i] != chars[i-1] {
+			key = append(key, chars[i])
+		}
+	}
+
+	if len(key) > 1 {
+		key = bytesTrimSuffix(key, 'S')
+		if n := len(key); n > 2 && key[n-2] == 'A' && key[n-1] == 'Y' {
+			key = append(key[:n-2], 'Y')
+		}
+		key = bytesTrimSuffix(key, 'A')
+	}
+	return string(key[:min(len(key), nysiisMaxLen)])
+}
+
+func bytesTrimSuffix(b []byte, c byte) []byte {
+	if len(b) > 0 && b[len(b)-1] == c {
+		return b[:len(b)-1]
+	}
+	return b
+}
+
+// Metaphone returns the (original) Metaphone code of word, e.g. "K0RN" for "Catherine" ("0" stands for "th").
+// It returns "" if word has no letters.
+func Metaphone(word string) string {
+	w := phoneticLetters(word, false)
+	if w == "" {
+		return ""
+	}
+
+	// Initial letters.
+	switch {
+	case strings.HasPrefix(w, "AE"):
+		w = w[1:]
+	case len(w) > 1 && strings.Contains("GN KN PN WR", w[:2]):
+		w = w[1:]
+	case w[0] == 'X':
+		w = "S" + w[1:]
+	case strings.HasPrefix(w, "WH"):
+		w = "W" + w[2:]
+	}
+
+	at := func(i int) byte {
+		if i >= 0 && i < len(w) {
+			return w[i]
+		}
+		return 0
+	}
+	has := func(i int, s string) bool {
+		return i >= 0 && strings.HasPrefix(w[i:], s)
+	}
+	frontVowel := func(c byte) bool {
+		return c == 'E' || c == 'I' || c == 'Y'
+	}
+
+	var code strings.Builder
+	for i := 0; i < len(w); i++ {
+		c := w[i]
+		if c != 'C' && i > 0 && at(i-1) == c {
+			continue // Double letters are encoded once, except "CC".
+		}
+
+		switch c {
+		case 'A', 'E', 'I', 'O', 'U':
+			if i == 0 {
+				code.WriteByte(c)
+			}
+		case 'B':
+			if !(i == len(w)-1 && at(i-1) == 'M') { // Silent in "-mb", e.g. "thumb".
+				code.WriteByte('B')
+			}
+		case 'C':
+			switch {
+			case has(i, "CIA") || (has(i, "CH") && at(i-1) != 'S'):
+				code.WriteByte('X')
+			case frontVowel(at(i + 1)):
+				if at(i-1) != 'S' { // Silent in "sci", "sce", "scy".
+					code.WriteByte('S')
+				}
+			default:
+				code.WriteByte('K')
+			}
+		case 'D':
+			if has(i, "DG") && frontVowel(at(i+2)) {
+				code.WriteByte('J')
+				i++
+			} else {
+				code.WriteByte('T')
+			}
+		case 'G':
+			switch {
+			case at(i+1) == 'H' && i+2 < len(w) && !isVowel(at(i+2)): // "night".
+			case i > 0 && (has(i, "GN") && i+2 == len(w) || has(i, "GNED") && i+4 == len(w)): // "sign", "signed".
+			case frontVowel(at(i+1)) && at(i-1) != 'G':
+				code.WriteByte('J')
+			default:
+				code.WriteByte('K')
+			}
+		case 'H':
+			// Silent after a vowel if no vowel follows ("Sarah"), and in "ch", "sh", "ph", "th" and "gh".
+			silent := (isVowel(at(i-1)) && !isVowel(at(i+1))) || (i > 0 && strings.IndexByte("CSPTG", at(i-1)) >= 0)
+			if !silent {
+				code.WriteByte('H')
+			}
+		case 'K':
+			if at(i-1) != 'C' {
+				code.WriteByte('K')
+			}
+		case 'P':
+			if at(i+1) == 'H' {
+				code.WriteByte('F')
+			} else {
+				code.WriteByte('P')
+			}
+		case 'Q':
+			code.WriteByte('K')
+		case 'S':
+			switch {
+			case has(i, "SH") || has(i, "SIO") || has(i, "SIA"):
+				code.WriteByte('X')
+			default:
+				code.WriteByte('S')
+			}
+		case 'T':
+			switch {
+			case has(i, "TIA") || has(i, "TIO"):
+				code.WriteByte('X')
+			case has(i, "TH"):
+				code.WriteByte('0')
+			case !has(i, "TCH"):
+				code.WriteByte('T')
+			}
+		case 'V':
+			code.WriteByte('F')
+		case 'W', 'Y':
+			if isVowel(at(i + 1)) {
+				code.WriteByte(c)
+			}
+		case 'X':
+			code.WriteString("KS")
+		case 'Z':
+			code.WriteByte('S')
+		default: // F, J, L, M, N and R.
+			code.WriteByte(c)
+		}
+	}
+	return code.String()
+}
+
+// doubleMetaphoneLen is the length of Double Metaphone codes.
+const doubleMetaphoneLen = 4
+
+/*
+DoubleMetaphone returns the primary and alternate Double Metaphone codes of word, e.g. "XMT" and "SMT" for "Schmidt".
+Double Metaphone improves on Metaphone, and knows about spellings from other languages (Germanic, Slavic, Italian, Spanish...).
+When the pronunciation is ambiguous the codes differ, otherwise they are the same.
+It returns "", "" if word has no letters.
+*/
+func DoubleMetaphone(word string) (string, string) {
+	w := strings.Join(strings.Fields(phoneticLetters(word, true)), " ")
+	if w == "" {
+		return "", ""
+	}
+	dm := doubleMetaphone{w: w, last: len(w) - 1}
+	dm.slavoGermanic = strings.ContainsAny(w, "WK") || strings.Contains(w, "CZ") || strings.Contains(w, "WITZ")
+	dm.encode()
+
+	primary, alternate := dm.primary.String(), dm.alternate.String()
+	return primary[:min(len(primary), doubleMetaphoneLen)], alternate[:min(len(alternate), doubleMetaphoneLen)]
+}
+
+type doubleMetaphone struct {
+	w                  string
+	last               int
+	slavoGermanic      bool
+	primary, alternate strings.Builder
+}
+
+// at returns the letter at i, or a space if i is out of range.
+func (dm *doubleMetaphone) at(i int) byte {
+	if i < 0 || i > dm.last {
+		return ' '
+	}
+	return dm.w[i]
+}
+
+// has reports whether one of subs starts at i.
+func (dm *doubleMetaphone) has(i int, subs ...string) bool {
+	if i < 0 {
+		return false
+	}
+	for _, s := range subs {
+		if strings.HasPrefix(dm.w[i:]+" ", s) { // The padding lets subs end in a space, e.g. "VAN ".
+			return true
+		}
+	}
+	return false
+}
+
+func (dm *doubleMetaphone) vowel(i int) bool {
+	return strings.IndexByte("AEIOUY", dm.at(i)) >= 0
+}
+
+// add adds codes to the primary and alternate codes, a single code is added to both.
+func (dm *doubleMetaphone) add(codes ...string) {
+	dm.primary.WriteString(codes[0])
+	dm.alternate.WriteString(codes[len(codes)-1])
+}
+
+// germanic reports whether the word looks Germanic ("van ...", "von ...", "sch...").
+func (dm *doubleMetaphone) germanic() bool {
+	return dm.has(0, "VAN ", "VON ", "SCH")
+}
+
+func (dm *doubleMetaphone) encode() {
+	i := 0
+	if dm.has(0, "GN", "KN", "PN", "WR", "PS") {
+		i++ // Silent first letter.
+	}
+	if dm.at(0) == 'X' {
+		dm.add("S") // "Xavier".
+		i++
+	}
+
+	for i <= dm.last && (dm.primary.Len() < doubleMetaphoneLen || dm.alternate.Len() < doubleMetaphoneLen) {
+		i = dm.letter(i)
+	}
+}
+
+// skip returns the position after the letter at i, skipping a double letter.
+func (dm *doubleMetaphone) skip(i int) int {
+	if dm.at(i+1) == dm.at(i) {
+		return i + 2
+	}
+	return i + 1
+}
+
+// letter encodes the letter at i, and returns the position of the next letter to encode.
+func (dm *doubleMetaphone) letter(i int) int {
+	switch c := dm.at(i); c {
+	case 'A', 'E', 'I', 'O', 'U', 'Y':
+		if i == 0 {
+			dm.add("A") // All initial vowels are encoded as "A".
+		}
+		return i + 1
+	case 'B':
+		dm.add("P")
+		return dm.skip(i)
+	case 'C':
+		return dm.c(i)
+	case 'D':
+		switch {
+		case dm.has(i, "DG") && dm.has(i+2, "I", "E", "Y"): // "edge".
+			dm.add("J")
+			return i + 3
+		case dm.has(i, "DG"): // "Edgar".
+			dm.add("TK")
+			return i + 2
+		case dm.has(i, "DT", "DD"):
+			dm.add("T")
+			return i + 2
+		}
+		dm.add("T")
+		return i + 1
+	case 'G':
+		return dm.g(i)
+	case 'H':
+		// Only keep H between vowels, or at the start before a vowel.
+		if (i == 0 || dm.vowel(i-1)) && dm.vowel(i+1) {
+			dm.add("H")
+			return i + 2
+		}
+		return i + 1
+	case 'J':
+		return dm.j(i)
+	case 'L':
+		if dm.at(i+1) == 'L' {
+			// Spanish "ll", e.g. "Cabrillo", "Gallegos".
+			if (i == dm.last-2 && dm.has(i-1, "ILLO", "ILLA", "ALLE")) ||
+				((dm.has(dm.last-1, "AS", "OS") || dm.has(dm.last, "A", "O")) && dm.has(i-1, "ALLE")) {
+				dm.add("L", "")
+				return i + 2
+			}
+			dm.add("L")
+			return i + 2
+		}
+		dm.add("L")
+		return i + 1
+	case 'M':
+		dm.add("M")
+		if (dm.has(i-1, "UMB") && (i+1 == dm.last || dm.has(i+2, "ER"))) || dm.at(i+1) == 'M' { // "dumb", "thumb".
+			return i + 2
+		}
+		return i + 1
+	case 'P':
+		if dm.at(i+1) == 'H' {
+			dm.add("F")
+			return i + 2
+		}
+		dm.add("P")
+		if dm.has(i+1, "P", "B") { // "Campbell", "raspberry".
+			return i + 2
+		}
+		return i + 1
+	case 'Q':
+		dm.add("K")
+		return dm.skip(i)
+	case 'R':
+		// French "-ier", e.g. "Rogier", but not "Meier" or "Maier".
+		if i == dm.last && !dm.slavoGermanic && dm.has(i-2, "IE") && !dm.has(i-4, "ME", "MA") {
+			dm.add("", "R")
+		} else {
+			dm.add("R")
+		}
+		return dm.skip(i)
+	case 'S':
+		return dm.s(i)
+	case 'T':
+		return dm.t(i)
+	case 'V':
+		dm.add("F")
+		return dm.skip(i)
+	case 'W':
+		return dm.wLetter(i)
+	case 'X':
+		// French "-iaux", "-eaux", "-aux", "-oux" are silent.
+		if !(i == dm.last && (dm.has(i-3, "IAU", "EAU") || dm.has(i-2, "AU", "OU"))) {
+			dm.add("KS")
+		}
+		if dm.has(i+1, "C", "X") {
+			return i + 2
+		}
+		return i + 1
+	case 'Z':
+		switch {
+		case dm.at(i+1) == 'H': // Chinese "Zhao".
+			dm.add("J")
+			return i + 2
+		case dm.has(i+1, "ZO", "ZI", "ZA") || (dm.slavoGermanic && i > 0 && dm.at(i-1) != 'T'):
+			dm.add("S", "TS")
+		default:
+			dm.add("S")
+		}
+		return dm.skip(i)
+	case 'F', 'K', 'N':
+		dm.add(string(c))
+		return dm.skip(i)
+	}
+	return i + 1 // Spaces.
+}
+
+func (dm *doubleMetaphone) c(i int) int {
+	switch {
+	// Germanic "ach", e.g. "Bacher", "Macher".
+	case i > 1 && !dm.vowel(i-2) && dm.has(i-1, "ACH") && dm.at(i+2) != 'I' &&
+		(dm.at(i+2) != 'E' || dm.has(i-2, "BACHER", "MACHER")):
+		dm.add("K")
+		return i + 2
+	case i == 0 && dm.has(i, "CAESAR"):
+		dm.add("S")
+		return i + 2
+	case dm.has(i, "CHIA"): // Italian "Chianti".
+		dm.add("K")
+		return i + 2
+	case dm.has(i, "CH"):
+		switch {
+		case i > 0 && dm.has(i, "CHAE"): // "Michael".
+			dm.add("K", "X")
+		case i == 0 && (dm.has(i+1, "HARAC", "HARIS") || dm.has(i+1, "HOR", "HYM", "HIA", "HEM")) && !dm.has(0, "CHORE"):
+			dm.add("K") // Greek roots, e.g. "chemistry", "chorus".
+		case dm.germanic() || dm.has(i-2, "ORCHES", "ARCHIT", "ORCHID") || dm.has(i+2, "T", "S") ||
+			((dm.has(i-1, "A", "O", "U", "E") || i == 0) && dm.has(i+2, "L", "R", "N", "M", "B", "H", "F", "V", "W", " ")):
+			dm.add("K")
+		case i > 0 && dm.has(0, "MC"): // "McHugh".
+			dm.add("K")
+		case i > 0:
+			dm.add("X", "K")
+		default:
+			dm.add("X")
+		}
+		return i + 2
+	case dm.has(i, "CZ") && !dm.has(i-2, "WICZ"): // "Czerny".
+		dm.add("S", "X")
+		return i + 2
+	case dm.has(i+1, "CIA"): // Italian "focaccia".
+		dm.add("X")
+		return i + 3
+	case dm.has(i, "CC") && !(i == 1 && dm.at(0) == 'M'): // Double C, but not "McClellan".
+		if dm.has(i+2, "I", "E", "H") && !dm.has(i+2, "HU") {
+			if (i == 1 && dm.at(i-1) == 'A') || dm.has(i-1, "UCCEE", "UCCES") { // "accident", "success".
+				dm.add("KS")
+			} else { // "bacci", "bertucci".
+				dm.add("X")
+			}
+			return i + 3
+		}
+		dm.add("K")
+		return i + 2
+	case dm.has(i, "CK", "CG", "CQ"):
+		dm.add("K")
+		return i + 2
+	case dm.has(i, "CI", "CE", "CY"):
+		if dm.has(i, "CIO", "CIE", "CIA") { // Italian.
+			dm.add("S", "X")
+		} else {
+			dm.add("S")
+		}
+		return i + 2
+	}
+
+	dm.add("K")
+	switch {
+	case dm.has(i+1, " C", " Q", " G"): // "Mac Caffrey", "Mac Gregor".
+		return i + 3
+	case dm.has(i+1, "C", "K", "Q") && !dm.has(i+1, "CE", "CI"):
+		return i + 2
+	}
+	return i + 1
+}
+
+func (dm *doubleMetaphone) g(i int) int {
+	switch {
+	case dm.at(i+1) == 'H':
+		if i > 0 && !dm.vowel(i-1) {
+			dm.add("K")
+			return i + 2
+		}
+		if i == 0 { // "Ghislane", "Ghiradelli".
+			if dm.at(i+2) == 'I' {
+				dm.add("J")
+			} else {
+				dm.add("K")
+			}
+			return i + 2
+		}
+		// Parker's rule (with some further refinements), e.g. "Hugh".
+		if (i > 1 && dm.has(i-2, "B", "H", "D")) || (i > 2 && dm.has(i-3, "B", "H", "D")) || (i > 3 && dm.has(i-4, "B", "H")) {
+			return i + 2
+		}
+		if i > 2 && dm.at(i-1) == 'U' && dm.has(i-3, "C", "G", "L", "R", "T") { // "laugh", "tough".
+			dm.add("F")
+		} else if dm.at(i-1) != 'I' {
+			dm.add("K")
+		}
+		return i + 2
+	case dm.at(i+1) == 'N':
+		switch {
+		case i == 1 && dm.vowel(0) && !dm.slavoGermanic:
+			dm.add("KN", "N")
+		case !dm.has(i+2, "EY") && dm.at(i+1) != 'Y' && !dm.slavoGermanic: // Not "Cagney".
+			dm.add("N", "KN")
+		default:
+			dm.add("KN")
+		}
+		return i + 2
+	case dm.has(i+1, "LI") && !dm.slavoGermanic: // "Tagliaro".
+		dm.add("KL", "L")
+		return i + 2
+	case i == 0 && (dm.at(i+1) == 'Y' || dm.has(i+1, "ES", "EP", "EB", "EL", "EY", "IB", "IL", "IN", "IE", "EI", "ER")):
+		dm.add("K", "J")
+		return i + 2
+	case (dm.has(i+1, "ER") || dm.at(i+1) == 'Y') && !dm.has(0, "DANGER", "RANGER", "MANGER") &&
+		!dm.has(i-1, "E", "I") && !dm.has(i-1, "RGY", "OGY"):
+		dm.add("K", "J") // "-ger-", "-gy-".
+		return i + 2
+	case dm.has(i+1, "E", "I", "Y") || dm.has(i-1, "AGGI", "OGGI"): // Italian "biaggi".
+		switch {
+		case dm.germanic() || dm.has(i+1, "ET"):
+			dm.add("K")
+		case dm.has(i+1, "IER "):
+			dm.add("J")
+		default:
+			dm.add("J", "K")
+		}
+		return i + 2
+	}
+
+	dm.add("K")
+	return dm.skip(i)
+}
+
+func (dm *doubleMetaphone) j(i int) int {
+	if dm.has(i, "JOSE") || dm.has(0, "SAN ") { // Spanish "Jose", "San Jacinto".
+		if (i == 0 && dm.at(i+4) == ' ') || dm.has(0, "SAN ") {
+			dm.add("H")
+		} else {
+			dm.add("J", "H")
+		}
+		return i + 1
+	}
+
+	switch {
+	case i == 0:
+		dm.add("J", "A") // "Yankelovich", "Jankelowicz".
+	case dm.vowel(i-1) && !dm.slavoGermanic && (dm.at(i+1) == 'A' || dm.at(i+1) == 'O'): // Spanish "bajador".
+		dm.add("J", "H")
+	case i == dm.last:
+		dm.add("J", "")
+	case !dm.has(i+1, "L", "T", "K", "S", "N", "M", "B", "Z") && !dm.has(i-1, "S", "K", "L"):
+		dm.add("J")
+	}
+	return dm.skip(i)
+}
+
+func (dm *doubleMetaphone) s(i int) int {
+	switch {
+	case dm.has(i-1, "ISL", "YSL"): // "island", "carlisle".
+		return i + 1
+	case i == 0 && dm.has(i, "SUGAR"):
+		dm.add("X", "S")
+		return i + 1
+	case dm.has(i, "SH"):
+		if dm.has(i+1, "HEIM", "HOEK", "HOLM", "HOLZ") { // Germanic.
+			dm.add("S")
+		} else {
+			dm.add("X")
+		}
+		return i + 2
+	case dm.has(i, "SIO", "SIA") || dm.has(i, "SIAN"): // Italian and Armenian.
+		if dm.slavoGermanic {
+			dm.add("S")
+		} else {
+			dm.add("S", "X")
+		}
+		return i + 3
+	case (i == 0 && dm.has(i+1, "M", "N", "L", "W")) || dm.has(i+1, "Z"):
+		// German and anglicizations, e.g. "Smith" and "Schmidt", "snider" and "Schneider".
+		dm.add("S", "X")
+		if dm.has(i+1, "Z") {
+			return i + 2
+		}
+		return i + 1
+	case dm.has(i, "SC"):
+		switch {
+		case dm.at(i+2) == 'H':
+			switch {
+			case dm.has(i+3, "ER", "EN"): // "Schenker".
+				dm.add("X", "SK")
+			case dm.has(i+3, "OO", "UY", "ED", "EM"): // Dutch "school", "schooner".
+				dm.add("SK")
+			case i == 0 && !dm.vowel(3) && dm.at(3) != 'W':
+				dm.add("X", "S")
+			default:
+				dm.add("X")
+			}
+		case dm.has(i+2, "I", "E", "Y"):
+			dm.add("S")
+		default:
+			dm.add("SK")
+		}
+		return i + 3
+	}
+
+	if i == dm.last && dm.has(i-2, "AI", "OI") { // French "resnais", "artois".
+		dm.add("", "S")
+	} else {
+		dm.add("S")
+	}
+	if dm.has(i+1, "S", "Z") {
+		return i + 2
+	}
+	return i + 1
+}
+
+func (dm *doubleMetaphone) t(i int) int {
+	switch {
+	case dm.has(i, "TION"):
+		dm.add("X")
+		return i + 3
+	case dm.has(i, "TIA", "TCH"):
+		dm.add("X")
+		return i + 3
+	case dm.has(i, "TH", "TTH"):
+		if dm.has(i+2, "OM", "AM") || dm.germanic() { // "Thomas", "Thames".
+			dm.add("T")
+		} else {
+			dm.add("0", "T")
+		}
+		return i + 2
+	}
+
+	dm.add("T")
+	if dm.has(i+1, "T", "D") {
+		return i + 2
+	}
+	return i + 1
+}
+
+func (dm *doubleMetaphone) wLetter(i int) int {
+	if dm.has(i, "WR") {
+		dm.add("R")
+		return i + 2
+	}
+
+	if i == 0 && (dm.vowel(i+1) || dm.has(i, "WH")) {
+		if dm.vowel(i + 1) { // "Wasserman" can also be "Vasserman".
+			dm.add("A", "F")
+		} else {
+			dm.add("A")
+		}
+	}
+
+	switch {
+	case (i == dm.last && dm.vowel(i-1)) || dm.has(i-1, "EWSKI", "EWSKY", "OWSKI", "OWSKY") || dm.has(0, "SCH"):
+		dm.add("", "F") // Polish, e.g. "Filipowicz".
+		return i + 1
+	case dm.has(i, "WICZ", "WITZ"):
+		dm.add("TS", "FX")
+		return i + 4
+	}
+	return i + 1
+}
@@ -0,0 +1,133 @@
+package nlp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSoundex(t *testing.T) {
+	var cases = []struct {
+		word     string
+		expected string
+	}{
+		{"Robert", "R163"},
+		{"Rupert", "R163"},
+		{"Rubin", "R150"},
+		{"Ashcraft", "A261"},
+		{"Tymczak", "T522"},
+		{"Pfister", "P236"},
+		{"Honeyman", "H555"},
+		{"Lee", "L000"},
+		{"o'Hara", "O600"},
+		{"Müller", "M460"},
+		{"42", ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.word, func(t *testing.T) {
+			require.Equal(t, tc.expected, Soundex(tc.word))
+		})
+	}
+}
+
+func TestNYSIIS(t *testing.T) {
+	var cases = []struct {
+		word     string
+		expected string
+	}{
+		{"Brian", "BRAN"},
+		{"Brown", "BRAN"},
+		{"Brun", "BRAN"},
+		{"Capp", "CAP"},
+		{"Cope", "CAP"},
+		{"Kipp", "CAP"},
+		{"Dane", "DAN"},
+		{"Dean", "DAN"},
+		{"Dent", "DAD"},
+		{"Smith", "SNAT"},
+		{"Schmit", "SNAT"},
+		{"Schmidt", "SNAD"},
+		{"Trueman", "TRANAN"},
+		{"Truman", "TRANAN"},
+		{"Macintosh", "MCANT"},
+		{"", ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.word, func(t *testing.T) {
+			require.Equal(t, tc.expected, NYSIIS(tc.word))
+		})
+	}
+}
+
+func TestMetaphone(t *testing.T) {
+	var cases = []struct {
+		word     string
+		expected string
+	}{
+		{"Catherine", "K0RN"},
+		{"Kathryn", "K0RN"},
+		{"Thumb", "0M"},
+		{"Knight", "NT"},
+		{"Wright", "RT"},
+		{"Xavier", "SFR"},
+		{"Phillip", "FLP"},
+		{"Science", "SNS"},
+		{"Judge", "JJ"},
+		{"Ghost", "KST"},
+		{"Nation", "NXN"},
+		{"Sherlock", "XRLK"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.word, func(t *testing.T) {
+			require.Equal(t, tc.expected, Metaphone(tc.word))
+		})
+	}
+}
+
+func TestDoubleMetaphone(t *testing.T) {
+	var cases = []struct {
+		word      string
+		primary   string
+		alternate string
+	}{
+		{"Smith", "SM0", "XMT"},
+		{"Schmidt", "XMT", "SMT"},
+		{"Thomas", "TMS", "TMS"},
+		{"Jose", "HS", "HS"},
+		{"Xavier", "SF", "SFR"},
+		{"Michael", "MKL", "MXL"},
+		{"Gallegos", "KLKS", "KKS"},
+		{"Cabrillo", "KPRL", "KPR"},
+		{"Edge", "AJ", "AJ"},
+		{"Tough", "TF", "TF"},
+		{"Hugh", "H", "H"},
+		{"Caesar", "SSR", "SSR"},
+		{"Chianti", "KNT", "KNT"},
+		{"Accident", "AKST", "AKST"},
+		{"Wasserman", "ASRM", "FSRM"},
+		{"Filipowicz", "FLPT", "FLPF"},
+		{"Zhao", "J", "J"},
+		{"Catherine", "K0RN", "KTRN"},
+		{"Kathryn", "K0RN", "KTRN"},
+		{"", "", ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.word, func(t *testing.T) {
+			primary, alternate := DoubleMetaphone(tc.word)
+			require.Equal(t, tc.primary, primary)
+			require.Equal(t, tc.alternate, alternate)
+		})
+	}
+}
+
+func TestPhoneticFilter(t *testing.T) {
+	a := Analyzer{Filters: []Filter{PhoneticFilter(Soundex)}}
+	require.Equal(t, a.Tokenize("Smith met Smyth"), []string{"S530", "M300", "S530"})
+
+	a = Analyzer{Filters: []Filter{DoubleMetaphoneFilter}}
+	require.Equal(t, []string{"SM0", "XMT", "XMT", "SMT"}, a.Tokenize("Smith Schmidt"))
+}
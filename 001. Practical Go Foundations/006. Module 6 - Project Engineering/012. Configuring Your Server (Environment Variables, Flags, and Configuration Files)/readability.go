@@ -0,0 +1,248 @@
+package nlp
+
+import (
+	"errors"
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+/*
+Readability.
+Readability formulas estimate how hard a text is to read from the length of its sentences and of its words.
+Most of them need the number of syllables in a word, which CountSyllables estimates from the vowel groups in its spelling,
+with a dictionary for the words the rules get wrong.
+Tokenize drops punctuation, so the sentences and words are counted here.
+*/
+
+// TextStats are the counts the readability formulas use.
+type TextStats struct {
+	Sentences     int `json:"sentences"`
+	Words         int `json:"words"`
+	Syllables     int `json:"syllables"`
+	Letters       int `json:"letters"`
+	Polysyllables int `json:"polysyllables"` // Words with 3 or more syllables.
+	ComplexWords  int `json:"complex_words"` // Polysyllables, except proper nouns, compounds and words that are only long because of "-es", "-ed" or "-ing".
+}
+
+// ReadabilityScores are the scores of the readability formulas.
+// All of them, except FleschReadingEase, are US school grade levels.
+type ReadabilityScores struct {
+	FleschReadingEase  float64   `json:"flesch_reading_ease"` // 0 (very hard) to 100 (very easy), can go beyond.
+	FleschKincaidGrade float64   `json:"flesch_kincaid_grade"`
+	GunningFog         float64   `json:"gunning_fog"`
+	SMOG               float64   `json:"smog"` // Meant for texts with 30 sentences or more.
+	ColemanLiau        float64   `json:"coleman_liau"`
+	Stats              TextStats `json:"stats"`
+}
+
+var (
+	// sentenceEndRe matches the end of a sentence: punctuation, closing quotes or brackets, and spaces (or the end of the text).
+	sentenceEndRe = regexp.MustCompile(`[.!?]+["'”’)\]]*(?:\s+|$)`)
+	// sentenceWordRe matches words, including contractions and hyphenated words.
+	sentenceWordRe = regexp.MustCompile(`\p{L}+(?:['’-]\p{L}+)*`)
+
+	// abbreviations don't end a sentence when they're followed by a period.
+	abbreviations = map[string]bool{
+		"mr": true, "mrs": true, "ms": true, "dr": true, "st": true, "prof": true, "sr": true, "jr": true,
+		"vs": true, "etc": true, "no": true, "mt": true, "col": true, "gen": true,
+	}
+)
+
+// SplitSentences splits text into sentences, at ".", "!" and "?".
+// A period after an abbreviation (e.g. "Mr.", "p.m."), an initial (e.g. "J. Watson"), or in a number ("3.14") doesn't end a sentence.
+func SplitSentences(text string) []string {
+	var sentences []string
+	for _, span := range sentenceSpans(text) {
+		sentences = append(sentences, text[span[0]:span[1]])
+	}
+	return sentences
+}
+
+// sentenceSpans returns the start and end offsets of the sentences in text, without the spaces around them.
+func sentenceSpans(text string) [][2]int {
+	var spans [][2]int
+	add := func(start, end int) {
+		s := text[start:end]
+		trimmed := strings.TrimSpace(s)
+		if trimmed != "" {
+			start += len(s) - len(strings.TrimLeftFunc(s, unicode.IsSpace))
+			spans = append(spans, [2]int{start, start + len(trimmed)})
+		}
+	}
+
+	start := 0
+	for _, loc := range sentenceEndRe.FindAllStringIndex(text, -1) {
+		if loc[1] < len(text) && !endsSentence(text[start:loc[0]], text[loc[0]:loc[1]]) {
+			continue
+		}
+		add(start, loc[1])
+		start = loc[1]
+	}
+	add(start, len(text))
+	return spans
+}
+
+// endsSentence reports whether punct (e.g. ". ") ends the sentence before.
+func endsSentence(before, punct string) bool {
+	if punct[0] != '.' || strings.HasPrefix(punct, "..") {
+		return true
+	}
+	i := strings.LastIndexFunc(before, unicode.IsSpace)
+	last := strings.ToLower(strings.TrimLeft(before[i+1:], `"'“‘(`))
+	if abbreviations[last] || strings.Contains(last, ".") { // "Mr.", "p.m."
+		return false
+	}
+	r, size := utf8.DecodeRuneInString(last)
+	return !(size == len(last) && unicode.IsLetter(r)) // An initial.
+}
+
+// Readability returns the readability scores of text, which must have at least one word.
+func Readability(text string) (ReadabilityScores, error) {
+	st := ComputeTextStats(text)
+	if st.Words == 0 {
+		return ReadabilityScores{}, errors.New("no words in text")
+	}
+
+	words, sentences := float64(st.Words), float64(st.Sentences)
+	wordsPerSentence := words / sentences
+	syllablesPerWord := float64(st.Syllables) / words
+	return ReadabilityScores{
+		FleschReadingEase:  206.835 - 1.015*wordsPerSentence - 84.6*syllablesPerWord,
+		FleschKincaidGrade: 0.39*wordsPerSentence + 11.8*syllablesPerWord - 15.59,
+		GunningFog:         0.4 * (wordsPerSentence + 100*float64(st.ComplexWords)/words),
+		SMOG:               1.0430*math.Sqrt(float64(st.Polysyllables)*30/sentences) + 3.1291,
+		ColemanLiau:        0.0588*(100*float64(st.Letters)/words) - 0.296*(100*sentences/words) - 15.8,
+		Stats:              st,
+	}, nil
+}
+
+// ComputeTextStats counts the sentences, words, syllables and letters in text.
+func ComputeTextStats(text string) TextStats {
+	var st TextStats
+	for _, sentence := range SplitSentences(text) {
+		words := sentenceWordRe.FindAllString(sentence, -1)
+		if len(words) == 0 {
+			continue
+		}
+		st.Sentences++
+		for i, word := range words {
+			n := CountSyllables(word)
+			st.Words++
+			st.Syllables += n
+			for _, r := range word {
+				if unicode.IsLetter(r) {
+					st.Letters++
+				}
+			}
+			if n >= 3 {
+				st.Polysyllables++
+				if isComplexWord(word, i == 0) {
+					st.ComplexWords++
+				}
+			}
+		}
+	}
+	return st
+}
+
+// isComplexWord reports whether word, which has 3 syllables or more, counts as complex for the Gunning fog index.
+func isComplexWord(word string, first bool) bool {
+	if strings.ContainsAny(word, "-") {
+		return false // Compound word.
+	}
+	r, _ := utf8.DecodeRuneInString(word)
+	if unicode.IsUpper(r) && !first {
+		return false // Proper noun.
+	}
+	lower := strings.ToLower(word)
+	for _, suffix := range []string{"es", "ed", "ing"} {
+		if base, ok := strings.CutSuffix(lower, suffix); ok && CountSyllables(base) < 3 {
+			return false
+		}
+	}
+	return true
+}
+
+// SyllableExceptions maps lower case words to their number of syllables, for words the rules in CountSyllables get wrong.
+// You can add your own words to it.
+var SyllableExceptions = map[string]int{
+	"area": 3, "beautiful": 3, "being": 2, "business": 2, "create": 2, "created": 3, "creates": 2, "creating": 3,
+	"every": 2, "idea": 3, "ideas": 3, "lion": 2, "naive": 2, "people": 2, "poem": 2, "poems": 2, "poet": 2,
+	"recipe": 3, "science": 2, "sciences": 3, "simile": 3, "something": 2, "sometimes": 2, "somewhere": 2,
+	"real": 1, "really": 2, "via": 2, "whereas": 2, "wednesday": 2,
+}
+
+var (
+	// subSyllableRes match vowel groups that are pronounced as one syllable less than they look ("-cial", "-tion").
+	subSyllableRes = compileAll(`cial`, `tia`, `cius`, `cious`, `giu`, `ion`, `iou`, `sia$`, `.ely$`)
+	// addSyllableRes match vowel groups that are pronounced as one syllable more ("ia", "-ism", "-ble").
+	addSyllableRes = compileAll(`ia`, `riet`, `dien`, `iu`, `io`, `ii`, `[aeiouym]bl$`, `[aeiou]{3}`, `^mc`, `ism$`,
+		`[^l]lien`, `^coa[dglx].`, `[^gq]ua[^auieo]`, `dnt$`)
+	vowelGroupRe = regexp.MustCompile(`[aeiouy]+`)
+)
+
+func compileAll(exprs ...string) []*regexp.Regexp {
+	res := make([]*regexp.Regexp, len(exprs))
+	for i, expr := range exprs {
+		res[i] = regexp.MustCompile(expr)
+	}
+	return res
+}
+
+// CountSyllables estimates the number of syllables in an English word. It returns 0 if word has no letters.
+func CountSyllables(word string) int {
+	word = strings.ToLower(word)
+	if strings.Contains(word, "-") {
+		n := 0
+		for _, part := range strings.Split(word, "-") {
+			n += CountSyllables(part)
+		}
+		return n
+	}
+	if n, ok := SyllableExceptions[word]; ok {
+		return n
+	}
+	silentE := !strings.HasSuffix(word, "é") // "café".
+
+	word = strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' {
+			return r
+		}
+		return -1
+	}, accentStripper.Normalize(word))
+	switch {
+	case word == "":
+		return 0
+	case len(word) <= 3:
+		return 1
+	}
+
+	// Silent endings: "makes" -> "make", "jumped" -> "jumpe", and then the final "e" -> "mak", "jump".
+	if base, ok := strings.CutSuffix(word, "es"); ok && !strings.ContainsAny(base[len(base)-1:], "sxzcgh") {
+		word = base + "e"
+	} else if base, ok := strings.CutSuffix(word, "ed"); ok && !strings.ContainsAny(base[len(base)-1:], "td") {
+		word = base + "e"
+	}
+	if silentE {
+		word = strings.TrimSuffix(word, "e")
+	}
+
+	n := len(vowelGroupRe.FindAllString(word, -1))
+	for _, re := range subSyllableRes {
+		if re.MatchString(word) {
+			n--
+		}
+	}
+	for _, re := range addSyllableRes {
+		if re.MatchString(word) {
+			n++
+		}
+	}
+	if l := len(word); l >= 3 && word[l-1] == 'l' && word[l-2] == word[l-3] && !strings.ContainsRune("aeiouy", rune(word[l-2])) {
+		n++ // Double consonant and "l", e.g. "little" -> "littl".
+	}
+	return max(n, 1)
+}
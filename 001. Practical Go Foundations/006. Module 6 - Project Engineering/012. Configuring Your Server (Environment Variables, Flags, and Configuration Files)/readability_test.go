@@ -0,0 +1,87 @@
+package nlp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountSyllables(t *testing.T) {
+	var cases = []struct {
+		word     string
+		expected int
+	}{
+		{"the", 1},
+		{"cake", 1},
+		{"makes", 1},
+		{"jumped", 1},
+		{"played", 1},
+		{"wanted", 2},
+		{"boxes", 2},
+		{"horses", 2},
+		{"table", 2},
+		{"tables", 2},
+		{"little", 2},
+		{"whale", 1},
+		{"Sherlock", 2},
+		{"Holmes", 1},
+		{"quiet", 2},
+		{"beautiful", 3},
+		{"nation", 2},
+		{"special", 2},
+		{"readability", 5},
+		{"immediately", 5},
+		{"people", 2},
+		{"idea", 3},
+		{"well-known", 2},
+		{"don't", 1},
+		{"café", 2},
+		{"", 0},
+		{"42", 0},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.word, func(t *testing.T) {
+			require.Equal(t, tc.expected, CountSyllables(tc.word))
+		})
+	}
+}
+
+func TestSplitSentences(t *testing.T) {
+	text := `Mr. Holmes came in at 3.15 p.m. with Dr. J. Watson. "Is it you?" he asked! Yes... it was`
+	expected := []string{
+		`Mr. Holmes came in at 3.15 p.m. with Dr. J. Watson.`,
+		`"Is it you?"`,
+		`he asked!`,
+		`Yes...`,
+		`it was`,
+	}
+	require.Equal(t, expected, SplitSentences(text))
+	require.Nil(t, SplitSentences("  "))
+}
+
+func TestReadability(t *testing.T) {
+	easy := "The cat sat on the mat. The dog ran to the cat. It was fun."
+	hard := "The administration's comprehensive infrastructure modernization initiative necessitates considerable interdepartmental coordination, particularly regarding environmental sustainability considerations."
+
+	e, err := Readability(easy)
+	require.NoError(t, err)
+	require.Equal(t, TextStats{Sentences: 3, Words: 15, Syllables: 15, Letters: 42}, e.Stats)
+	require.InDelta(t, 117.2, e.FleschReadingEase, 0.1)
+	require.InDelta(t, -1.8, e.FleschKincaidGrade, 0.1)
+
+	h, err := Readability(hard)
+	require.NoError(t, err)
+	require.Less(t, h.FleschReadingEase, 0.0)
+	for _, pair := range [][2]float64{
+		{e.FleschKincaidGrade, h.FleschKincaidGrade},
+		{e.GunningFog, h.GunningFog},
+		{e.SMOG, h.SMOG},
+		{e.ColemanLiau, h.ColemanLiau},
+	} {
+		require.Less(t, pair[0], pair[1])
+	}
+
+	_, err = Readability("... 42!")
+	require.Error(t, err)
+}
@@ -0,0 +1,259 @@
+package nlp
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+/*
+Redaction.
+Tokenize throws away the layout of the original text, so it can't be used to scrub documents.
+Redact works on the spans found by the extractors (which keep their original offsets),
+and replaces them in place, leaving the rest of the text untouched.
+*/
+
+// Action is what Redact does with a sensitive span.
+type Action string
+
+const (
+	Keep        Action = "keep"        // Leave the span as is.
+	Mask        Action = "mask"        // "555-1234" -> "***-****".
+	Placeholder Action = "placeholder" // "555-1234" -> "[PHONE]".
+	Pseudonym   Action = "pseudonym"   // "555-1234" -> "[PHONE_3f9a1c2b]", the same for every occurrence.
+)
+
+// Policy configures Redact.
+type Policy struct {
+	// Actions maps kinds to actions. Kinds that are not in Actions use Default.
+	Actions map[Kind]Action `json:"actions"`
+	// Default is the action for kinds that are not in Actions, Placeholder if empty.
+	Default Action `json:"default"`
+	// Key is used to derive pseudonyms. The same key always gives the same pseudonym for the same value.
+	Key string `json:"key"`
+	// Names is a gazetteer of names (e.g. "Sherlock Holmes") to redact.
+	Names []string `json:"names"`
+}
+
+// Validate returns an error if p has unknown actions, or uses Pseudonym without a Key.
+// Without a key, pseudonyms of short values (e.g. phone numbers) can be brute forced back to the original.
+func (p Policy) Validate() error {
+	actions := []Action{p.Default}
+	for _, a := range p.Actions {
+		actions = append(actions, a)
+	}
+	for _, a := range actions {
+		switch a {
+		case "", Keep, Mask, Placeholder:
+		case Pseudonym:
+			if p.Key == "" {
+				return fmt.Errorf("%q action requires a key", a)
+			}
+		default:
+			return fmt.Errorf("unknown action: %q", a)
+		}
+	}
+	return nil
+}
+
+func (p Policy) action(k Kind) Action {
+	if a, ok := p.Actions[k]; ok && a != "" {
+		return a
+	}
+	if p.Default != "" {
+		return p.Default
+	}
+	return Placeholder
+}
+
+// Redaction is a span of the original text that Redact replaced.
+// It doesn't contain the original text, so it's safe to log.
+type Redaction struct {
+	Kind        Kind   `json:"kind"`
+	Start       int    `json:"start"` // Byte offset in the original text.
+	End         int    `json:"end"`
+	Replacement string `json:"replacement"`
+}
+
+var (
+	// CreditCardExtractor finds Luhn-valid credit card numbers, e.g. "4111 1111 1111 1111" -> "4111111111111111".
+	// Numbers are either 13 to 19 contiguous digits, or groups of 4 digits (4-6-5 or 4-6-4 for Amex and Diners).
+	// Trailing groups that make the number invalid (e.g. "4111 1111 1111 1111 12/25") are dropped.
+	CreditCardExtractor = &Extractor{
+		Kind:      CreditCard,
+		Pattern:   regexp.MustCompile(`\b(?:\d{4}(?:[ -]\d{4}){2,3}(?:[ -]\d{1,4})?|\d{4}[ -]\d{6}[ -]\d{4,5}|\d{13,19})\b`),
+		Normalize: normalizeCreditCard,
+		Prefixes:  creditCardPrefixes,
+	}
+
+	// IPExtractor finds IPv4 and IPv6 addresses.
+	IPExtractor = &Extractor{
+		Kind:      IPAddress,
+		Pattern:   regexp.MustCompile(`\b(?:\d{1,3}(?:\.\d{1,3}){3}|[0-9a-fA-F]{0,4}(?::[0-9a-fA-F]{0,4}){2,7})\b`),
+		Normalize: normalizeIP,
+	}
+
+	// RedactExtractors are the extractors Redact uses, in order of priority.
+	RedactExtractors = []*Extractor{
+		EmailExtractor,
+		CreditCardExtractor,
+		IPExtractor,
+		PhoneExtractor,
+	}
+)
+
+// NameExtractor returns an extractor for the names in gazetteer.
+// Names are matched as whole words, ignoring case, and the longest name wins ("Sherlock Holmes" over "Sherlock").
+func NameExtractor(gazetteer []string) *Extractor {
+	var names []string
+	for _, name := range gazetteer {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	// The Matcher's word boundaries know about non-ASCII letters, unlike \b in regular expressions ("José", "Zoë").
+	m, err := NewMatcher(names, MatcherOptions{IgnoreCase: true, WholeWords: true, LeftmostLongest: true})
+	if err != nil {
+		return nil // Can't happen, there are no empty names.
+	}
+	return &Extractor{
+		Kind: Name,
+		Find: func(text string) [][]int {
+			var locs [][]int
+			for _, match := range m.FindAll(text) {
+				locs = append(locs, []int{match.Start, match.End})
+			}
+			return locs
+		},
+	}
+}
+
+// LoadGazetteer reads names from r, one per line. Empty lines and lines starting with "#" are ignored.
+func LoadGazetteer(r io.Reader) ([]string, error) {
+	var names []string
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		names = append(names, line)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// Redact returns text with the emails, credit card numbers, IP addresses, phone numbers and gazetteer names in it replaced
+// according to policy, and the list of replacements it made.
+func Redact(text string, policy Policy) (string, []Redaction) {
+	extractors := RedactExtractors
+	if e := NameExtractor(policy.Names); e != nil {
+		extractors = append(slices.Clone(extractors), e)
+	}
+
+	var (
+		b          strings.Builder
+		redactions []Redaction
+		pos        int
+	)
+	for _, tok := range Extract(text, extractors...) {
+		var repl string
+		switch policy.action(tok.Kind) {
+		case Keep:
+			continue
+		case Mask:
+			repl = mask(tok.Text)
+		case Pseudonym:
+			repl = pseudonym(tok, policy.Key)
+		default:
+			repl = "[" + strings.ToUpper(tok.Kind.String()) + "]"
+		}
+
+		b.WriteString(text[pos:tok.Start])
+		b.WriteString(repl)
+		pos = tok.End
+		redactions = append(redactions, Redaction{Kind: tok.Kind, Start: tok.Start, End: tok.End, Replacement: repl})
+	}
+	b.WriteString(text[pos:])
+	return b.String(), redactions
+}
+
+// mask replaces letters and digits with "*", keeping separators so the layout is preserved.
+func mask(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return '*'
+		}
+		return r
+	}, s)
+}
+
+// pseudonym returns a stable replacement derived from the normalized value of tok.
+func pseudonym(tok Token, key string) string {
+	value := tok.Value
+	if tok.Kind == Name {
+		value = strings.ToLower(value)
+	}
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(tok.Kind.String() + ":" + value))
+	sum := hex.EncodeToString(mac.Sum(nil))
+	return "[" + strings.ToUpper(tok.Kind.String()) + "_" + sum[:8] + "]"
+}
+
+func normalizeCreditCard(s string) (string, bool) {
+	digits := strings.NewReplacer(" ", "", "-", "").Replace(s)
+	if len(digits) < 13 || len(digits) > 19 || !luhn(digits) {
+		return "", false
+	}
+	return digits, true
+}
+
+// creditCardPrefixes returns the prefixes of s that end before a separator, longest first.
+func creditCardPrefixes(s string) []string {
+	var prefixes []string
+	for i := len(s) - 1; i > 0; i-- {
+		if s[i] == ' ' || s[i] == '-' {
+			prefixes = append(prefixes, s[:i])
+		}
+	}
+	return prefixes
+}
+
+// luhn reports whether digits passes the Luhn checksum.
+func luhn(digits string) bool {
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+func normalizeIP(s string) (string, bool) {
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return "", false
+	}
+	return ip.String(), true
+}
@@ -0,0 +1,111 @@
+package nlp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedact(t *testing.T) {
+	var cases = []struct {
+		name   string
+		text   string
+		policy Policy
+		out    string
+	}{
+		{
+			"placeholder",
+			"Mail holmes@baker.st or call 555-123-4567.",
+			Policy{},
+			"Mail [EMAIL] or call [PHONE].",
+		},
+		{
+			"mask",
+			"Card 4111 1111 1111 1111 from 10.0.0.1",
+			Policy{Default: Mask},
+			"Card **** **** **** **** from **.*.*.*",
+		},
+		{
+			"expiry date",
+			"Card 4111 1111 1111 1111 12/25 ok",
+			Policy{},
+			"Card [CREDIT_CARD] 12/25 ok",
+		},
+		{
+			"amex",
+			"Amex 3782 822463 10005.",
+			Policy{},
+			"Amex [CREDIT_CARD].",
+		},
+		{
+			"not luhn",
+			"Order 4111111111111112 shipped",
+			Policy{},
+			"Order 4111111111111112 shipped",
+		},
+		{
+			"keep",
+			"Mail holmes@baker.st from 10.0.0.1",
+			Policy{Actions: map[Kind]Action{Email: Keep}},
+			"Mail holmes@baker.st from [IP_ADDRESS]",
+		},
+		{
+			"names",
+			"Sherlock Holmes met Dr. Watson.",
+			Policy{Names: []string{"Sherlock", "Sherlock Holmes", "Watson"}},
+			"[NAME] met Dr. [NAME].",
+		},
+		{
+			"non-ASCII names",
+			"Ask José or ZOË Ångström today, not Josébel.",
+			Policy{Names: []string{"José", "Zoë Ångström", "Ångström"}},
+			"Ask [NAME] or [NAME] today, not Josébel.",
+		},
+		{
+			"bare phone",
+			"Call 5551234567 or +445551234567.",
+			Policy{},
+			"Call [PHONE] or [PHONE].",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, _ := Redact(tc.text, tc.policy)
+			require.Equal(t, tc.out, out)
+		})
+	}
+}
+
+func TestRedactPseudonym(t *testing.T) {
+	policy := Policy{Default: Pseudonym, Key: "secret"}
+	text := "From holmes@baker.st to watson@baker.st, cc HOLMES@baker.st"
+	out, redactions := Redact(text, policy)
+	require.Len(t, redactions, 3)
+	require.Equal(t, redactions[0].Replacement, redactions[2].Replacement)
+	require.NotEqual(t, redactions[0].Replacement, redactions[1].Replacement)
+	require.NotContains(t, out, "baker")
+	require.True(t, strings.HasPrefix(redactions[0].Replacement, "[EMAIL_"))
+
+	// Offsets point into the original text.
+	r := redactions[1]
+	require.Equal(t, "watson@baker.st", text[r.Start:r.End])
+
+	// A different key gives different pseudonyms.
+	_, other := Redact(text, Policy{Default: Pseudonym, Key: "other"})
+	require.NotEqual(t, redactions[0].Replacement, other[0].Replacement)
+}
+
+func TestPolicyValidate(t *testing.T) {
+	require.NoError(t, Policy{Actions: map[Kind]Action{Email: Mask}}.Validate())
+	require.Error(t, Policy{Default: "shred"}.Validate())
+	require.Error(t, Policy{Actions: map[Kind]Action{Phone: Pseudonym}}.Validate())
+	require.NoError(t, Policy{Actions: map[Kind]Action{Phone: Pseudonym}, Key: "secret"}.Validate())
+}
+
+func TestLoadGazetteer(t *testing.T) {
+	names, err := LoadGazetteer(strings.NewReader("# Characters\nSherlock Holmes\n\n  Irene Adler \n"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"Sherlock Holmes", "Irene Adler"}, names)
+}
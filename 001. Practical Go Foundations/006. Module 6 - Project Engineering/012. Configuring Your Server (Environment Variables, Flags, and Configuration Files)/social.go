@@ -0,0 +1,178 @@
+package nlp
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"nlp/stemmer"
+)
+
+/*
+Social media text.
+Posts are full of tokens that wordRe either drops or breaks: "#golang", "@gopher", "$GOOG", ":-)", "👍🏽" or "👩‍💻".
+The extractors below keep them whole, as typed tokens.
+
+An emoji can be several code points that display as one picture:
+	- A skin tone modifier after a person: "👍" + "🏽" = "👍🏽".
+	- A zero width joiner (ZWJ, U+200D) between emoji: "👩" + ZWJ + "💻" = "👩‍💻".
+	- Two regional indicator letters for a flag: "🇫" + "🇷" = "🇫🇷".
+	- A variation selector (U+FE0F) that asks for the emoji style of a symbol: "❤" + U+FE0F = "❤️".
+	  Symbols such as "©", "™" or "❤" are text by default, they're only emoji with it.
+*/
+
+const (
+	// emojiPresentation are the code points displayed as emoji by default (Emoji_Presentation in Unicode's emoji data).
+	// Outside the BMP, all the pictographs from U+1F000 are taken as emoji.
+	emojiPresentation = `[\x{231A}\x{231B}\x{23E9}-\x{23EC}\x{23F0}\x{23F3}\x{25FD}\x{25FE}\x{2614}\x{2615}\x{2648}-\x{2653}` +
+		`\x{267F}\x{2693}\x{26A1}\x{26AA}\x{26AB}\x{26BD}\x{26BE}\x{26C4}\x{26C5}\x{26CE}\x{26D4}\x{26EA}\x{26F2}\x{26F3}` +
+		`\x{26F5}\x{26FA}\x{26FD}\x{2705}\x{270A}\x{270B}\x{2728}\x{274C}\x{274E}\x{2753}-\x{2755}\x{2757}\x{2795}-\x{2797}` +
+		`\x{27B0}\x{27BF}\x{2B1B}\x{2B1C}\x{2B50}\x{2B55}\x{1F000}-\x{1FAFF}]`
+
+	// emojiText are symbols displayed as text by default ("©", "™", "❤"), they're emoji only with U+FE0F or a skin tone.
+	emojiText = `[\x{00A9}\x{00AE}\x{203C}\x{2049}\x{2122}\x{2139}\x{2194}-\x{2199}\x{21A9}\x{21AA}\x{2328}\x{23CF}` +
+		`\x{23ED}-\x{23EF}\x{23F1}\x{23F2}\x{23F8}-\x{23FA}\x{24C2}\x{25AA}\x{25AB}\x{25B6}\x{25C0}\x{25FB}\x{25FC}` +
+		`\x{2600}-\x{27BF}\x{2934}\x{2935}\x{2B05}-\x{2B07}\x{3030}\x{303D}\x{3297}\x{3299}]`
+
+	skinTone = `[\x{1F3FB}-\x{1F3FF}]`
+
+	// emojiPattern matches one emoji, with its modifiers.
+	emojiPattern = `(?:[\x{1F1E6}-\x{1F1FF}]{2}|[#*0-9]\x{FE0F}?\x{20E3}|` +
+		`(?:` + emojiPresentation + `\x{FE0F}?` + skinTone + `?|` + emojiText + `(?:\x{FE0F}` + skinTone + `?|` + skinTone + `))` +
+		`[\x{E0020}-\x{E007F}]*)`
+)
+
+var (
+	// HashtagExtractor finds hashtags, e.g. "#GoLang" -> "#golang". A hashtag has at least one letter, "#1" isn't one.
+	HashtagExtractor = &Extractor{
+		Kind:       Hashtag,
+		Pattern:    regexp.MustCompile(`#[\p{L}\p{M}\p{N}_]*\p{L}[\p{L}\p{M}\p{N}_]*`),
+		Normalize:  func(s string) (string, bool) { return strings.ToLower(s), true },
+		WholeWords: true, // Not "#tag" in "café#tag".
+	}
+
+	// MentionExtractor finds user mentions, e.g. "@Gopher" -> "@gopher". It doesn't match e-mail addresses.
+	// Handles are ASCII: "@José" isn't a mention, rather than "@Jos" followed by "é".
+	MentionExtractor = &Extractor{
+		Kind:       Mention,
+		Pattern:    regexp.MustCompile(`@[A-Za-z0-9_]{1,30}`),
+		Normalize:  func(s string) (string, bool) { return strings.ToLower(s), true },
+		WholeWords: true,
+	}
+
+	// CashtagExtractor finds stock tickers, e.g. "$goog" -> "$GOOG" or "$BRK.B".
+	CashtagExtractor = &Extractor{
+		Kind:       Cashtag,
+		Pattern:    regexp.MustCompile(`\$[A-Za-z]{1,6}(?:\.[A-Za-z]{1,2})?`),
+		Normalize:  func(s string) (string, bool) { return strings.ToUpper(s), true },
+		WholeWords: true,
+	}
+
+	// EmoticonExtractor finds ASCII emoticons, e.g. ":-)", ";P", ":'(", "<3" or "^_^".
+	EmoticonExtractor = &Extractor{
+		Kind: Emoticon,
+		Pattern: regexp.MustCompile(`(?:\B[>]?[:;=]'?[-o*^]?(?:[)\](\[/\\|*]\B|[DPpOo3]\b)|` + // Eyes first: ":-)", ";P".
+			`\B[(\[][-o]?[:;=]\B|` + // Mouth first: "(:".
+			`\B</?3\b|\bx[D]\b|\^_?\^|-_-|\b[oO]_[oO]\b|\bT_T\b)`),
+	}
+
+	// EmojiExtractor finds emoji, including skin tones, flags, keycaps and ZWJ sequences, e.g. "👩🏽‍💻".
+	EmojiExtractor = &Extractor{
+		Kind:    Emoji,
+		Pattern: regexp.MustCompile(emojiPattern + `(?:\x{200D}` + emojiPattern + `)*`),
+	}
+
+	// SocialExtractors are the extractors of TokenizeSocial, in order of priority.
+	SocialExtractors = []*Extractor{
+		URLExtractor,
+		EmailExtractor,
+		HashtagExtractor,
+		MentionExtractor,
+		CashtagExtractor,
+		EmojiExtractor,
+		EmoticonExtractor,
+	}
+)
+
+// SocialOptions are the options of TokenizeSocial.
+type SocialOptions struct {
+	// SegmentHashtags adds a Word token for every part of a camel case hashtag, after the Hashtag token:
+	// "#BlackLivesMatter" is followed by "black", "live" and "matter".
+	// Hashtags without case changes, digits or underscores, like "#golang", are not split.
+	SegmentHashtags bool
+}
+
+// TokenizeSocial tokenizes social media text: hashtags, mentions, cashtags, URLs, e-mails, emoticons and emoji are typed tokens,
+// the rest is split into Word tokens like TokenizeTyped does, but with letters in any script.
+func TokenizeSocial(text string, opts SocialOptions) []Token {
+	var tokens []Token
+	pos := 0
+	for _, tok := range Extract(text, SocialExtractors...) {
+		tokens = appendWords(tokens, text, pos, tok.Start, letterRe)
+		tokens = append(tokens, tok)
+		if tok.Kind == Hashtag && opts.SegmentHashtags {
+			tokens = appendHashtagWords(tokens, tok)
+		}
+		pos = tok.End
+	}
+	return appendWords(tokens, text, pos, len(text), letterRe)
+}
+
+// appendHashtagWords appends the parts of a camel case hashtag as Word tokens.
+func appendHashtagWords(tokens []Token, tag Token) []Token {
+	name := tag.Text[1:] // Without "#".
+	parts := SegmentCamelCase(name)
+	if len(parts) < 2 {
+		return tokens
+	}
+
+	offset := tag.Start + 1
+	for _, part := range parts {
+		start := offset + strings.Index(tag.Text[offset-tag.Start:], part)
+		offset = start + len(part)
+		tokens = append(tokens, Token{Kind: Word, Text: part, Value: stemmer.Stem(strings.ToLower(part)), Start: start, End: offset})
+	}
+	return tokens
+}
+
+// SegmentCamelCase splits s at case changes and between letters and digits, and at underscores:
+// "iPhone15Pro" -> ["i" "Phone" "15" "Pro"], "HTTPServer" -> ["HTTP" "Server"], "black_lives" -> ["black" "lives"].
+func SegmentCamelCase(s string) []string {
+	var parts []string
+	start := 0
+	var prev rune
+	for i, r := range s {
+		_, size := utf8.DecodeRuneInString(s[i:])
+		if i > 0 && splitsCamelCase(prev, r, s[i+size:]) {
+			parts = append(parts, s[start:i])
+			start = i
+		}
+		prev = r
+	}
+	parts = append(parts, s[start:])
+
+	out := parts[:0]
+	for _, p := range parts {
+		if p = strings.Trim(p, "_"); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// splitsCamelCase reports whether a word ends between prev and r, followed by rest.
+func splitsCamelCase(prev, r rune, rest string) bool {
+	switch {
+	case r == '_' || prev == '_':
+		return true
+	case unicode.IsDigit(prev) != unicode.IsDigit(r):
+		return true
+	case unicode.IsLower(prev) && unicode.IsUpper(r):
+		return true
+	case unicode.IsUpper(prev) && unicode.IsUpper(r): // "HTTPServer": split before "S", the last capital before lower case.
+		next, _ := utf8.DecodeRuneInString(rest)
+		return unicode.IsLower(next)
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package nlp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenizeSocialKinds(t *testing.T) {
+	var cases = []struct {
+		text  string
+		kind  Kind
+		match string
+		value string
+	}{
+		{"Learning #GoLang today", Hashtag, "#GoLang", "#golang"},
+		{"Top #10Things", Hashtag, "#10Things", "#10things"},
+		{"Thanks @Gopher_42!", Mention, "@Gopher_42", "@gopher_42"},
+		{"Bought $goog again", Cashtag, "$goog", "$GOOG"},
+		{"and $BRK.B too", Cashtag, "$BRK.B", "$BRK.B"},
+		{"Read https://go.dev/blog now", URL, "https://go.dev/blog", "https://go.dev/blog"},
+		{"Mail gopher@go.dev please", Email, "gopher@go.dev", "gopher@go.dev"},
+		{"Great :-)", Emoticon, ":-)", ":-)"},
+		{"nice ;P", Emoticon, ";P", ";P"},
+		{"so sad :'(", Emoticon, ":'(", ":'("},
+		{"love <3", Emoticon, "<3", "<3"},
+		{"happy ^_^", Emoticon, "^_^", "^_^"},
+		{"Yes 👍🏽", Emoji, "👍🏽", "👍🏽"},                  // Skin tone.
+		{"Family 👨‍👩‍👧 trip", Emoji, "👨‍👩‍👧", "👨‍👩‍👧"}, // ZWJ sequence.
+		{"Coding 👩🏽‍💻 all day", Emoji, "👩🏽‍💻", "👩🏽‍💻"},
+		{"Vive la 🇫🇷", Emoji, "🇫🇷", "🇫🇷"},  // Flag.
+		{"I ❤️ it", Emoji, "❤️", "❤️"},     // Variation selector.
+		{"Press 1️⃣", Emoji, "1️⃣", "1️⃣"}, // Keycap.
+		{"Done ✅", Emoji, "✅", "✅"},
+		{"Sunny ☀️ day", Emoji, "☀️", "☀️"},
+		{"Me ☝🏽", Emoji, "☝🏽", "☝🏽"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.text, func(t *testing.T) {
+			var typed []Token
+			for _, tok := range TokenizeSocial(tc.text, SocialOptions{}) {
+				if tok.Kind != Word {
+					typed = append(typed, tok)
+				}
+			}
+			require.Len(t, typed, 1)
+			tok := typed[0]
+			require.Equal(t, tc.kind, tok.Kind)
+			require.Equal(t, tc.match, tok.Text)
+			require.Equal(t, tc.value, tok.Value)
+			require.Equal(t, tc.match, tc.text[tok.Start:tok.End])
+		})
+	}
+}
+
+func TestTokenizeSocialNotTyped(t *testing.T) {
+	for _, text := range []string{"Acme™", "©2024", "® and ☀", "● ★ ✓ → ↔", "I ❤ it", "issue #1", "at 10:30", "a$b", "x@y", "C# rocks", "pay $5", "Thanks @José and @Zoë", "café#tag", "ça$GOOG", "@this_handle_is_much_longer_than_thirty_characters"} {
+		for _, tok := range TokenizeSocial(text, SocialOptions{}) {
+			require.Equal(t, Word, tok.Kind, "%s: %q", text, tok.Text)
+		}
+	}
+}
+
+func TestTokenizeSocial(t *testing.T) {
+	text := "Thanks @rob_pike! #BlackLivesMatter 🙌🏿 :)"
+	var kinds []Kind
+	var values []string
+	for _, tok := range TokenizeSocial(text, SocialOptions{SegmentHashtags: true}) {
+		kinds = append(kinds, tok.Kind)
+		values = append(values, tok.Value)
+		require.Equal(t, tok.Text, text[tok.Start:tok.End])
+	}
+	require.Equal(t, []Kind{Word, Mention, Hashtag, Word, Word, Word, Emoji, Emoticon}, kinds)
+	require.Equal(t, []string{"thank", "@rob_pike", "#blacklivesmatter", "black", "live", "matter", "🙌🏿", ":)"}, values)
+
+	tokens := TokenizeSocial("#golang", SocialOptions{SegmentHashtags: true})
+	require.Len(t, tokens, 1)
+}
+
+func TestSegmentCamelCase(t *testing.T) {
+	var cases = []struct {
+		text     string
+		expected []string
+	}{
+		{"BlackLivesMatter", []string{"Black", "Lives", "Matter"}},
+		{"iPhone15Pro", []string{"i", "Phone", "15", "Pro"}},
+		{"HTTPServer", []string{"HTTP", "Server"}},
+		{"black_lives__matter", []string{"black", "lives", "matter"}},
+		{"golang", []string{"golang"}},
+		{"ÉtéÀParis", []string{"Été", "À", "Paris"}},
+		{"\xff\xfe", []string{"\xff\xfe"}},
+		{"Ab\xffCd", []string{"Ab\xffCd"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.text, func(t *testing.T) {
+			require.Equal(t, tc.expected, SegmentCamelCase(tc.text))
+		})
+	}
+}
@@ -2,12 +2,27 @@ package stemmer
 
 import (
 	"strings"
+	"unicode/utf8"
 )
 
 var (
 	suffixes = []string{"s", "ing", "ed"}
+
+	// languageSuffixes are the suffixes StemLanguage removes, by language (longest first).
+	languageSuffixes = map[string][]string{
+		"de": {"ungen", "heit", "keit", "ung", "ern", "en", "er", "es", "e", "s"},
+		"nl": {"heden", "heid", "ingen", "ing", "en", "e", "s"},
+		"fr": {"ements", "ement", "ations", "ation", "ées", "ée", "es", "er", "ez", "é", "e", "s"},
+		"es": {"amientos", "amiento", "aciones", "ación", "mente", "es", "os", "as", "o", "a", "s"},
+		"it": {"amenti", "amento", "azioni", "azione", "mente", "i", "e", "o", "a"},
+		"pt": {"amentos", "amento", "ações", "ação", "mente", "os", "as", "o", "a", "s"},
+		"sv": {"heterna", "heten", "arna", "erna", "orna", "het", "are", "ar", "er", "or", "en", "et", "a", "e"},
+	}
 )
 
+// minStemLength is the shortest stem StemLanguage returns.
+const minStemLength = 3
+
 // Stem returns the stem of word.
 // E.g., "working" -> "work".
 func Stem(word string) string {
@@ -18,3 +33,20 @@ func Stem(word string) string {
 	}
 	return word
 }
+
+// StemLanguage returns the stem of word in lang (e.g. "de").
+// English uses Stem, languages without a stemmer return word unchanged.
+// E.g., "de", "zeitungen" -> "zeit".
+func StemLanguage(lang, word string) string {
+	if lang == "en" {
+		return Stem(word)
+	}
+
+	for _, suffix := range languageSuffixes[lang] {
+		stem, ok := strings.CutSuffix(word, suffix)
+		if ok && utf8.RuneCountInString(stem) >= minStemLength {
+			return stem
+		}
+	}
+	return word
+}
@@ -17,3 +17,14 @@ func ExampleStem() {
 	// working -> work
 	// works -> work
 }
+
+func ExampleStemLanguage() {
+	fmt.Println(stemmer.StemLanguage("de", "zeitungen"))
+	fmt.Println(stemmer.StemLanguage("es", "libertades"))
+	fmt.Println(stemmer.StemLanguage("fi", "oikeuksiin")) // No Finnish stemmer.
+
+	// Output:
+	// zeit
+	// libertad
+	// oikeuksiin
+}
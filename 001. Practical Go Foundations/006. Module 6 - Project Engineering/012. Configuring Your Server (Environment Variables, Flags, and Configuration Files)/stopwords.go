@@ -0,0 +1,62 @@
+package nlp
+
+import (
+	"strings"
+)
+
+// stopWordLists are the most common function words, by language.
+var stopWordLists = map[string]string{
+	"en": "a an and are as at be but by for from has have he her his i in is it its of on or she that the their they this to was were will with you",
+	"de": "aber als am an auch auf aus bei bin bis das dass dem den der des die du ein eine einem einen einer er es für hat ich ihr im in ist mit nicht noch nur oder sich sie sind so und von war wir zu",
+	"fr": "au aux avec ce ces dans de des du elle en est et il ils je la le les leur lui mais me même ne nous on ou par pas pour qu que qui sa se ses son sur ta te tu un une vous",
+	"es": "al como con de del el en es esta este la las le lo los más mi no o para pero por que se si sin su sus un una y ya yo",
+	"it": "a al alla anche che chi ci come con da dei del della di e è gli ha ho i il in la le lo ma mi non per più se si sono su un una",
+	"pt": "a ao aos as com como da das de do dos e é ela ele em entre eu mas na não nas no nos o os ou para pela pelo por que se sem seu sua um uma",
+	"nl": "aan al als bij dat de den der die dit een en er het hij hun ik in is je maar met na niet nog of om ook op te van voor was wat we wij zij zijn",
+	"sv": "alla att av de dem den det du där efter en ett för från han har hon i inte jag med men mig min när och om på sig som till under var vi är",
+	"da": "af alle at de dem den der det du efter en er et for fra han har hun i ikke jeg med men mig min når og om på sig som til var vi",
+	"nb": "av alle at de dem den der det du eller en er et etter for fra han har hun i ikke jeg med men meg min når og om på seg som til var vi",
+	"fi": "ei en et hän he ja jo joka jos kun kuin me mikä minä mutta ne niin nyt on ole oli se sekä tai te tämä että vain",
+	"pl": "a ale bo by być co czy dla do go i ich jak jest już ja jego jej na nie o od po przez się są tak tego to w we z za że",
+	"cs": "a aby ale ani bez by do i já jak je jeho jejich jsem jsou k na nebo než o od po pro se si tak také to ve z za že",
+	"sk": "a aby ale ani bez by do i ja ako je jeho ich som sú k na alebo než o od po pre sa si tak tiež to v vo z za že",
+	"hu": "a az abban azt be de egy el és ez hogy is itt ki meg mert mint már nem nincs sem sok szerint van volt",
+	"ro": "a acest al ale am ca care cu de din după este ei el fi în iar la mai nu o pe pentru sau se să și un una",
+	"tr": "ama bir biz bu çok da daha de diye en gibi hem her için ile ise kadar ki mi ne o olan olarak sen şu ve veya ya",
+	"id": "ada adalah akan atau bahwa dalam dan dari di dengan ini itu juga ke kami karena mereka oleh pada saya sebagai tidak untuk yang",
+	"vi": "anh các có của cho chúng đã để được không là một này những nhưng rất tôi trên trong và về với",
+	"ru": "а без в во вы да для до его её если же за и из или к как мы на не но о он она они от по с так то что это я",
+	"uk": "а але в від все для до з за й і його ми на не ні він вона вони по про та так те ти у це що як я",
+	"el": "και να το η ο τα της του των σε με για από που στο στη είναι θα δεν αυτό ένα μια οι τις τη στην ως",
+}
+
+// stopWords is built from stopWordLists.
+var stopWords = make(map[string]map[string]bool)
+
+func init() {
+	for lang, list := range stopWordLists {
+		words := make(map[string]bool)
+		for _, w := range strings.Fields(list) {
+			words[w] = true
+		}
+		stopWords[lang] = words
+	}
+}
+
+// IsStopWord reports whether word (lower case) is a stop word in lang (e.g. "en").
+func IsStopWord(lang, word string) bool {
+	return stopWords[lang][word]
+}
+
+// StopWordFilter returns a Filter that removes the stop words of lang. Tokens must be lower case.
+func StopWordFilter(lang string) Filter {
+	return func(tokens []string) []string {
+		out := tokens[:0]
+		for _, tok := range tokens {
+			if !IsStopWord(lang, tok) {
+				out = append(out, tok)
+			}
+		}
+		return out
+	}
+}
@@ -0,0 +1,160 @@
+package nlp
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+/*
+Synonyms.
+A search for "car" should find documents about automobiles. A synonym filter adds (or substitutes) tokens while analyzing,
+so both the documents and the queries get the same tokens.
+Synonyms are in the Solr format, one rule per line:
+
+	# Equivalent words, every one is expanded to all of them (or replaced by the first if expand is false).
+	car, automobile, auto
+	# Explicit mappings: the words on the left are replaced by the words on the right.
+	i-pod, i pod => ipod
+	# Multi-word synonyms.
+	usa, united states, united states of america
+
+A comma in a word is escaped with a backslash ("\,").
+The rules are analyzed with the same tokenizer as the text, so that "automobiles" matches "automobile" after stemming.
+The Filter goes last in an Analyzer, after lower casing and stemming.
+Multi-word synonyms are added one token after the other, the stream of tokens has no positions to stack them.
+*/
+
+// Synonyms are synonym rules, see LoadSynonyms.
+type Synonyms struct {
+	rules  map[string][][]string // Tokens joined by " " -> replacements.
+	maxLen int                   // Number of tokens in the longest left hand side.
+}
+
+// LoadSynonyms reads rules in the Solr format, and tokenizes them with tokenize (e.g. Tokenize, or an Analyzer's Tokenize method).
+// If expand is false, equivalent words are all replaced by the first one instead of being expanded to all of them.
+func LoadSynonyms(r io.Reader, tokenize func(string) []string, expand bool) (*Synonyms, error) {
+	syn := &Synonyms{rules: make(map[string][][]string)}
+	s := bufio.NewScanner(r)
+	lnum := 0
+	for s.Scan() {
+		lnum++
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if err := syn.addRule(line, tokenize, expand); err != nil {
+			return nil, fmt.Errorf("%d: %w", lnum, err)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return syn, nil
+}
+
+func (syn *Synonyms) addRule(line string, tokenize func(string) []string, expand bool) error {
+	parts := strings.Split(line, "=>")
+	if len(parts) > 2 {
+		return errors.New(`more than one "=>"`)
+	}
+
+	left, err := splitSynonyms(parts[0], tokenize)
+	if err != nil {
+		return err
+	}
+	right := left
+	switch {
+	case len(parts) == 2:
+		if right, err = splitSynonyms(parts[1], tokenize); err != nil {
+			return err
+		}
+	case !expand:
+		right = left[:1]
+	}
+
+	for _, l := range left {
+		key := strings.Join(l, " ")
+		for _, rep := range right {
+			if !containsTokens(syn.rules[key], rep) {
+				syn.rules[key] = append(syn.rules[key], rep)
+			}
+		}
+		syn.maxLen = max(syn.maxLen, len(l))
+	}
+	return nil
+}
+
+// splitSynonyms splits a comma separated list of synonyms, and tokenizes them.
+func splitSynonyms(list string, tokenize func(string) []string) ([][]string, error) {
+	var synonyms [][]string
+	var sb strings.Builder
+	add := func() error {
+		tokens := tokenize(sb.String())
+		sb.Reset()
+		if len(tokens) == 0 {
+			return fmt.Errorf("empty synonym in %q", strings.TrimSpace(list))
+		}
+		synonyms = append(synonyms, tokens)
+		return nil
+	}
+
+	escaped := false
+	for _, r := range list {
+		switch {
+		case escaped:
+			sb.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == ',':
+			if err := add(); err != nil {
+				return nil, err
+			}
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	if err := add(); err != nil {
+		return nil, err
+	}
+	return synonyms, nil
+}
+
+func containsTokens(list [][]string, tokens []string) bool {
+	for _, l := range list {
+		if strings.Join(l, " ") == strings.Join(tokens, " ") {
+			return true
+		}
+	}
+	return false
+}
+
+// SynonymFilter returns a Filter that replaces the longest sequence of tokens that has synonyms by its synonyms.
+func SynonymFilter(syn *Synonyms) Filter {
+	return func(tokens []string) []string {
+		var out []string
+		for i := 0; i < len(tokens); {
+			n := 0
+			var reps [][]string
+			for l := min(syn.maxLen, len(tokens)-i); l > 0; l-- {
+				if r, ok := syn.rules[strings.Join(tokens[i:i+l], " ")]; ok {
+					n, reps = l, r
+					break
+				}
+			}
+			if n == 0 {
+				out = append(out, tokens[i])
+				i++
+				continue
+			}
+			for _, rep := range reps {
+				out = append(out, rep...)
+			}
+			i += n
+		}
+		return out
+	}
+}
@@ -0,0 +1,67 @@
+package nlp
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testSynonyms = `# Equivalent.
+car, automobile, auto
+usa, united states, united states of america
+
+# Mappings.
+i-pod, i pod => ipod
+colour => color
+tv => television, tv
+comma\, word => comma
+`
+
+func lowerFields(s string) []string {
+	return strings.Fields(strings.ToLower(s))
+}
+
+func TestSynonymFilter(t *testing.T) {
+	var cases = []struct {
+		text     string
+		expand   bool
+		expected string
+	}{
+		{"my car", true, "my car automobile auto"},
+		{"my Auto", true, "my car automobile auto"},
+		{"my automobile", false, "my car"},
+		{"the united states of america", true, "the usa united states united states of america"}, // Longest match.
+		{"the united states of europe", false, "the usa of europe"},
+		{"an i pod", true, "an ipod"},
+		{"i-pod colour", false, "ipod color"},
+		{"tv", false, "television tv"},
+		{"comma, word", true, "comma"}, // The escaped comma is in the rule.
+		{"no synonyms", true, "no synonyms"},
+	}
+	for _, tc := range cases {
+		t.Run(fmt.Sprintf("%s/%v", tc.text, tc.expand), func(t *testing.T) {
+			syn, err := LoadSynonyms(strings.NewReader(testSynonyms), lowerFields, tc.expand)
+			require.NoError(t, err)
+			tokens := SynonymFilter(syn)(lowerFields(tc.text))
+			require.Equal(t, tc.expected, strings.Join(tokens, " "))
+		})
+	}
+}
+
+func TestSynonymsTokenize(t *testing.T) {
+	syn, err := LoadSynonyms(strings.NewReader("automobile, car\n"), Tokenize, true)
+	require.NoError(t, err)
+
+	a := Analyzer{Filters: []Filter{LowerCaseFilter, StemFilter, SynonymFilter(syn)}}
+	require.Equal(t, []string{"fast", "automobile", "car"}, a.Tokenize("Fast automobiles"))
+	require.Equal(t, []string{"blue", "automobile", "car"}, a.Tokenize("Blue cars"))
+}
+
+func TestLoadSynonymsErrors(t *testing.T) {
+	for _, data := range []string{"a => b => c", "a, , b", "=> b", "a =>"} {
+		_, err := LoadSynonyms(strings.NewReader(data), lowerFields, true)
+		require.Error(t, err, data)
+	}
+}
@@ -0,0 +1,321 @@
+package nlp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+/*
+Vocabulary.
+Machine learning models work on numbers, not strings, so tokens are mapped to integer IDs.
+The IDs must be the same every time the vocabulary is built from the same corpus, and on every server,
+so the vocabulary is sorted by frequency (most frequent first) and then alphabetically, and can be saved and loaded.
+Rare tokens (typos, names) are left out, and are encoded as the <unk> token.
+*/
+
+// Special tokens, they always have the first IDs.
+const (
+	PadToken     = "<pad>" // Fills short sequences up to a fixed length.
+	UnknownToken = "<unk>" // Stands for tokens that are not in the vocabulary.
+)
+
+// IDs of the special tokens.
+const (
+	PadID = iota
+	UnknownID
+)
+
+var specialTokens = []string{PadToken, UnknownToken}
+
+// Vocabulary maps tokens to IDs and back.
+type Vocabulary struct {
+	tokens []string // ID -> token.
+	counts []int    // ID -> count in the corpus, 0 for special tokens.
+	ids    map[string]int
+}
+
+// VocabularyBuilder counts tokens and builds a Vocabulary from them.
+type VocabularyBuilder struct {
+	// MinFreq is the minimal count of a token to be in the vocabulary, 0 or 1 keeps all tokens.
+	MinFreq int
+	// MaxSize is the maximal size of the vocabulary, including the special tokens. 0 means no limit.
+	MaxSize int
+
+	counts map[string]int
+}
+
+// Add counts tokens, e.g. the output of Tokenize for one document.
+func (vb *VocabularyBuilder) Add(tokens []string) {
+	if vb.counts == nil {
+		vb.counts = make(map[string]int)
+	}
+	for _, tok := range tokens {
+		vb.counts[tok]++
+	}
+}
+
+// Build returns the vocabulary of the tokens added so far.
+func (vb *VocabularyBuilder) Build() (*Vocabulary, error) {
+	if vb.MaxSize != 0 && vb.MaxSize < len(specialTokens) {
+		return nil, fmt.Errorf("maximal size must be at least %d, got %d", len(specialTokens), vb.MaxSize)
+	}
+
+	type entry struct {
+		token string
+		count int
+	}
+	var entries []entry
+	for tok, count := range vb.counts {
+		if count >= vb.MinFreq && !isSpecialToken(tok) {
+			entries = append(entries, entry{tok, count})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].count != entries[j].count {
+			return entries[i].count > entries[j].count
+		}
+		return entries[i].token < entries[j].token
+	})
+	if vb.MaxSize != 0 {
+		entries = entries[:min(len(entries), vb.MaxSize-len(specialTokens))]
+	}
+
+	v := newVocabulary()
+	for _, e := range entries {
+		v.add(e.token, e.count)
+	}
+	return v, nil
+}
+
+// BuildVocabulary builds a Vocabulary from documents, every document being a list of tokens.
+func BuildVocabulary(docs [][]string, minFreq, maxSize int) (*Vocabulary, error) {
+	vb := VocabularyBuilder{MinFreq: minFreq, MaxSize: maxSize}
+	for _, doc := range docs {
+		vb.Add(doc)
+	}
+	return vb.Build()
+}
+
+func newVocabulary() *Vocabulary {
+	v := &Vocabulary{ids: make(map[string]int)}
+	for _, tok := range specialTokens {
+		v.add(tok, 0)
+	}
+	return v
+}
+
+func (v *Vocabulary) add(tok string, count int) {
+	v.ids[tok] = len(v.tokens)
+	v.tokens = append(v.tokens, tok)
+	v.counts = append(v.counts, count)
+}
+
+func isSpecialToken(tok string) bool {
+	for _, s := range specialTokens {
+		if tok == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Len returns the number of tokens in the vocabulary, including the special tokens.
+func (v *Vocabulary) Len() int {
+	return len(v.tokens)
+}
+
+// ID returns the ID of tok, UnknownID if it's not in the vocabulary.
+func (v *Vocabulary) ID(tok string) int {
+	if id, ok := v.ids[tok]; ok {
+		return id
+	}
+	return UnknownID
+}
+
+// Contains reports whether tok is in the vocabulary.
+func (v *Vocabulary) Contains(tok string) bool {
+	_, ok := v.ids[tok]
+	return ok
+}
+
+// Token returns the token for id, and false if there is no such token.
+func (v *Vocabulary) Token(id int) (string, bool) {
+	if id < 0 || id >= len(v.tokens) {
+		return "", false
+	}
+	return v.tokens[id], true
+}
+
+// Count returns how many times tok was seen when the vocabulary was built.
+func (v *Vocabulary) Count(tok string) int {
+	if id, ok := v.ids[tok]; ok {
+		return v.counts[id]
+	}
+	return 0
+}
+
+// Encode returns the IDs of tokens. Tokens that are not in the vocabulary are encoded as UnknownID.
+func (v *Vocabulary) Encode(tokens []string) []int {
+	ids := make([]int, len(tokens))
+	for i, tok := range tokens {
+		ids[i] = v.ID(tok)
+	}
+	return ids
+}
+
+// EncodePadded returns the IDs of tokens, truncated or padded with PadID to length.
+func (v *Vocabulary) EncodePadded(tokens []string, length int) []int {
+	ids := v.Encode(tokens[:min(len(tokens), length)])
+	for len(ids) < length {
+		ids = append(ids, PadID)
+	}
+	return ids
+}
+
+// Decode returns the tokens for ids. Padding is dropped, and unknown IDs are decoded as UnknownToken.
+func (v *Vocabulary) Decode(ids []int) []string {
+	tokens := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id == PadID {
+			continue
+		}
+		tok, ok := v.Token(id)
+		if !ok {
+			tok = UnknownToken
+		}
+		tokens = append(tokens, tok)
+	}
+	return tokens
+}
+
+/*
+Save writes the vocabulary in text format: one token per line, the line number (from 0) is the ID.
+Every line is the token as a Go quoted string, a space, and its count, e.g. `"holmes" 462`.
+*/
+func (v *Vocabulary) Save(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for i, tok := range v.tokens {
+		fmt.Fprintf(bw, "%s %d\n", strconv.Quote(tok), v.counts[i])
+	}
+	return bw.Flush()
+}
+
+// LoadVocabulary reads a vocabulary written by Save.
+func LoadVocabulary(r io.Reader) (*Vocabulary, error) {
+	v := &Vocabulary{ids: make(map[string]int)}
+	s := bufio.NewScanner(r)
+	lnum := 0
+	for s.Scan() {
+		lnum++
+		line := s.Text()
+		quoted, err := strconv.QuotedPrefix(line)
+		if err != nil {
+			return nil, fmt.Errorf("%d: bad token in %q", lnum, line)
+		}
+		tok, _ := strconv.Unquote(quoted)
+		count, err := strconv.Atoi(strings.TrimSpace(line[len(quoted):]))
+		if err != nil || count < 0 {
+			return nil, fmt.Errorf("%d: bad count in %q", lnum, line)
+		}
+		if err := v.check(tok); err != nil {
+			return nil, fmt.Errorf("%d: %w", lnum, err)
+		}
+		v.add(tok, count)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if len(v.tokens) < len(specialTokens) {
+		return nil, errors.New("missing special tokens")
+	}
+	return v, nil
+}
+
+// check returns an error if tok can't be the next token of v.
+func (v *Vocabulary) check(tok string) error {
+	if len(v.tokens) < len(specialTokens) {
+		if want := specialTokens[len(v.tokens)]; tok != want {
+			return fmt.Errorf("expected special token %q, got %q", want, tok)
+		}
+		return nil
+	}
+	if _, ok := v.ids[tok]; ok {
+		return fmt.Errorf("duplicate token %q", tok)
+	}
+	return nil
+}
+
+// vocabularyMagic starts the binary format, followed by a version byte.
+const vocabularyMagic = "NLPV"
+
+/*
+MarshalBinary implements encoding.BinaryMarshaler.
+The binary format is more compact and faster to load than the text format:
+the magic "NLPV", a version byte (1), the number of tokens, and then for every token its length, bytes and count.
+All numbers are unsigned varints.
+*/
+func (v *Vocabulary) MarshalBinary() ([]byte, error) {
+	buf := []byte(vocabularyMagic)
+	buf = append(buf, 1)
+	buf = binary.AppendUvarint(buf, uint64(len(v.tokens)))
+	for i, tok := range v.tokens {
+		buf = binary.AppendUvarint(buf, uint64(len(tok)))
+		buf = append(buf, tok...)
+		buf = binary.AppendUvarint(buf, uint64(v.counts[i]))
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (v *Vocabulary) UnmarshalBinary(data []byte) error {
+	rest, ok := bytes.CutPrefix(data, []byte(vocabularyMagic))
+	if !ok || len(rest) == 0 {
+		return errors.New("not a binary vocabulary")
+	}
+	if rest[0] != 1 {
+		return fmt.Errorf("unsupported binary vocabulary version: %d", rest[0])
+	}
+	r := bytes.NewReader(rest[1:])
+
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return fmt.Errorf("bad binary vocabulary: %w", err)
+	}
+	if n < uint64(len(specialTokens)) || n > uint64(r.Len()) {
+		return fmt.Errorf("bad binary vocabulary: %d tokens", n)
+	}
+
+	nv := Vocabulary{ids: make(map[string]int, n)}
+	for range n {
+		size, err := binary.ReadUvarint(r)
+		if err != nil {
+			return fmt.Errorf("bad binary vocabulary: %w", err)
+		}
+		if size > uint64(r.Len()) {
+			return io.ErrUnexpectedEOF
+		}
+		tok := make([]byte, size)
+		io.ReadFull(r, tok)
+		count, err := binary.ReadUvarint(r)
+		if err != nil {
+			return fmt.Errorf("bad binary vocabulary: %w", err)
+		}
+		if err := nv.check(string(tok)); err != nil {
+			return fmt.Errorf("bad binary vocabulary: %w", err)
+		}
+		nv.add(string(tok), int(count))
+	}
+	if r.Len() != 0 {
+		return fmt.Errorf("bad binary vocabulary: %d extra bytes", r.Len())
+	}
+
+	*v = nv
+	return nil
+}
@@ -0,0 +1,86 @@
+package nlp
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var vocabDocs = [][]string{
+	Tokenize("The dog chased the cat."),
+	Tokenize("The cat chased the mouse, the mouse ran."),
+	Tokenize("A dog barked."),
+}
+
+func TestVocabulary(t *testing.T) {
+	v, err := BuildVocabulary(vocabDocs, 2, 0)
+	require.NoError(t, err)
+
+	// Special tokens first, then by count, ties alphabetically.
+	var tokens []string
+	for id := range v.Len() {
+		tok, _ := v.Token(id)
+		tokens = append(tokens, tok)
+	}
+	require.Equal(t, []string{PadToken, UnknownToken, "the", "cat", "chas", "dog", "mouse"}, tokens)
+	require.Equal(t, 5, v.Count("the"))
+
+	ids := v.Encode(Tokenize("The dog barked at the fox"))
+	require.Equal(t, []int{2, 5, UnknownID, UnknownID, 2, UnknownID}, ids)
+	require.Equal(t, []string{"the", "dog", UnknownToken, UnknownToken, "the", UnknownToken}, v.Decode(ids))
+
+	require.Equal(t, []int{2, 3, PadID, PadID}, v.EncodePadded([]string{"the", "cat"}, 4))
+	require.Equal(t, []int{2}, v.EncodePadded([]string{"the", "cat"}, 1))
+	require.Equal(t, []string{"the"}, v.Decode([]int{2, PadID, PadID}))
+}
+
+func TestVocabularyMaxSize(t *testing.T) {
+	v, err := BuildVocabulary(vocabDocs, 0, 4)
+	require.NoError(t, err)
+	require.Equal(t, 4, v.Len())
+	require.True(t, v.Contains("the"))
+	require.True(t, v.Contains("cat"))
+	require.False(t, v.Contains("dog"))
+
+	// Tokens that look like special tokens don't get a second ID.
+	v, err = BuildVocabulary([][]string{{"<unk>", "<unk>", "a"}}, 0, 0)
+	require.NoError(t, err)
+	require.Equal(t, 3, v.Len())
+	require.Equal(t, UnknownID, v.ID("<unk>"))
+
+	_, err = BuildVocabulary(vocabDocs, 0, 1)
+	require.Error(t, err)
+}
+
+func TestVocabularySaveLoad(t *testing.T) {
+	v, err := BuildVocabulary(append(vocabDocs, []string{"two words", "\"quoted\"\n"}), 0, 0)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	err = v.Save(&buf)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(buf.String(), "\"<pad>\" 0\n\"<unk>\" 0\n\"the\" 5\n"))
+
+	v2, err := LoadVocabulary(&buf)
+	require.NoError(t, err)
+	require.Equal(t, v, v2)
+
+	data, err := v.MarshalBinary()
+	require.NoError(t, err)
+	var v3 Vocabulary
+	err = v3.UnmarshalBinary(data)
+	require.NoError(t, err)
+	require.Equal(t, v, &v3)
+
+	// Truncated data must fail, not panic.
+	for i := range data {
+		require.Error(t, v3.UnmarshalBinary(data[:i]))
+	}
+
+	_, err = LoadVocabulary(strings.NewReader("\"the\" 5\n"))
+	require.Error(t, err) // Missing special tokens.
+	_, err = LoadVocabulary(strings.NewReader("\"<pad>\" 0\n\"<unk>\" 0\n\"a\" 1\n\"a\" 1\n"))
+	require.Error(t, err) // Duplicate token.
+}